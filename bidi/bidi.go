@@ -2,15 +2,36 @@
 // Use of this source code is governed by a MIT
 // license that can be found in the LICENSE file.
 
-// Package bidi provides generic bidirectional one-to-one maps.
+// Package bidi provides generic bidirectional one-to-one and one-to-many
+// maps.
 package bidi
 
-import "sync"
+import (
+	"errors"
+	"sync"
+)
 
-// Map is an unordered generic bidirectional one-to-one map.
+var (
+	// ErrKeyExists is returned by [Map.PutStrict] when the key is already
+	// bound to a value.
+	ErrKeyExists = errors.New("key exists")
+	// ErrValueExists is returned by [Map.PutStrict] when the value is
+	// already bound to a key.
+	ErrValueExists = errors.New("value exists")
+)
+
+// Pair is a key and value pair of a [Map].
+type Pair[K, V comparable] struct {
+	Key K
+	Val V
+}
+
+// Map is an unordered generic bidirectional one-to-one map of keys K and
+// values V.
 //
 // It allows you to look up values by key and keys by value.
-// The zero value of the type is not usable, use [New] to create a new map.
+// The zero value of the type is not usable, use [NewMap] to create a new map
+// or [New] to create a map whose keys and values are of the same type.
 //
 // Example:
 //
@@ -18,26 +39,38 @@ import "sync"
 //	m.Put("key1", "value1")
 //	val, ok := m.Val("key1") // val == "value1", ok == true
 //	key, ok := m.Key("value1") // key == "key1", ok == true
-type Map[K comparable] struct {
-	z        K
-	keyToVal map[K]K
-	valToKey map[K]K
+type Map[K, V comparable] struct {
+	zk       K
+	zv       V
+	keyToVal map[K]V
+	valToKey map[V]K
 }
 
-// New returns a new [Map].
+// NewMap returns a new [Map] of keys K and values V.
 //
 // Example:
 //
-//	m := New[string]()
-//	m.Put("key1", "value1")
-func New[K comparable]() *Map[K] {
-	return &Map[K]{
-		z:        *new(K),
-		keyToVal: make(map[K]K),
-		valToKey: make(map[K]K),
+//	m := NewMap[int64, string]()
+//	m.Put(1, "alice")
+//	name, ok := m.Val(1)      // name == "alice", ok == true
+//	id, ok := m.Key("alice") // id == 1, ok == true
+func NewMap[K, V comparable]() *Map[K, V] {
+	return &Map[K, V]{
+		zk:       *new(K),
+		zv:       *new(V),
+		keyToVal: make(map[K]V),
+		valToKey: make(map[V]K),
 	}
 }
 
+// New returns a new [Map] whose keys and values are both of type K.
+//
+// Example:
+//
+//	m := New[string]()
+//	m.Put("key1", "value1")
+func New[K comparable]() *Map[K, K] { return NewMap[K, K]() }
+
 // Len returns number of pairs in the map.
 //
 // Example:
@@ -46,7 +79,7 @@ func New[K comparable]() *Map[K] {
 //	m.Put("key1", "value1")
 //	m.Put("key2", "value2")
 //	l := m.Len() // l == 2
-func (self *Map[K]) Len() int { return len(self.keyToVal) }
+func (self *Map[K, V]) Len() int { return len(self.keyToVal) }
 
 // KeyExists returns truth if key exists.
 //
@@ -62,7 +95,7 @@ func (self *Map[K]) Len() int { return len(self.keyToVal) }
 //	m.Put("key1", "value1")
 //	exists := m.KeyExists("key1") // exists == true
 //	exists := m.KeyExists("key2") // exists == false
-func (self *Map[K]) KeyExists(key K) (exists bool) {
+func (self *Map[K, V]) KeyExists(key K) (exists bool) {
 	_, exists = self.keyToVal[key]
 	return
 }
@@ -81,7 +114,7 @@ func (self *Map[K]) KeyExists(key K) (exists bool) {
 //	m.Put("key1", "value1")
 //	exists := m.ValExists("value1") // exists == true
 //	exists := m.ValExists("value2") // exists == false
-func (self *Map[K]) ValExists(value K) (exists bool) {
+func (self *Map[K, V]) ValExists(value V) (exists bool) {
 	_, exists = self.valToKey[value]
 	return
 }
@@ -102,7 +135,7 @@ func (self *Map[K]) ValExists(value K) (exists bool) {
 //	m.Put("key1", "value1")
 //	key, ok := m.Key("value1") // key == "key1", ok == true
 //	key, ok := m.Key("value2") // key == "", ok == false
-func (self *Map[K]) Key(value K) (key K, b bool) {
+func (self *Map[K, V]) Key(value V) (key K, b bool) {
 	key, b = self.valToKey[value]
 	return
 }
@@ -114,7 +147,7 @@ func (self *Map[K]) Key(value K) (key K, b bool) {
 //
 // Returns:
 //   - value: The value associated with the key. If the key is not found, the
-//     zero value of K is returned.
+//     zero value of V is returned.
 //   - b: True if the key was found, false otherwise.
 //
 // Example:
@@ -123,7 +156,7 @@ func (self *Map[K]) Key(value K) (key K, b bool) {
 //	m.Put("key1", "value1")
 //	value, ok := m.Val("key1") // value == "value1", ok == true
 //	value, ok := m.Val("key2") // value == "", ok == false
-func (self *Map[K]) Val(key K) (value K, b bool) {
+func (self *Map[K, V]) Val(key K) (value V, b bool) {
 	value, b = self.keyToVal[key]
 	return
 }
@@ -131,13 +164,17 @@ func (self *Map[K]) Val(key K) (value K, b bool) {
 // Put stores value under key and returns oldValue that was replaced and a
 // truth if value existed under key k and was replaced.
 //
+// If value was bound to another key that pair is removed to keep the map
+// one-to-one. Use [Map.PutReplace] to learn about all evicted pairs,
+// [Map.PutStrict] or [Map.TryPut] to refuse conflicting puts.
+//
 // Arguments:
 //   - key: The key to store the value under.
 //   - value: The value to store.
 //
 // Returns:
 //   - oldValue: The value that was previously stored under the key, or the
-//     zero value of K if no value was previously stored.
+//     zero value of V if no value was previously stored.
 //   - found: True if a value was previously stored under the key and was
 //     replaced, false otherwise.
 //
@@ -146,13 +183,106 @@ func (self *Map[K]) Val(key K) (value K, b bool) {
 //	m := New[string]()
 //	oldValue, found := m.Put("key1", "value1") // oldValue == "", found == false
 //	oldValue, found = m.Put("key1", "value2") // oldValue == "value1", found == true
-func (self *Map[K]) Put(key K, value K) (oldValue K, found bool) {
+func (self *Map[K, V]) Put(key K, value V) (oldValue V, found bool) {
 	if oldValue, found = self.keyToVal[key]; found {
 		delete(self.valToKey, oldValue)
 	}
+	if oldKey, exists := self.valToKey[value]; exists {
+		delete(self.keyToVal, oldKey)
+	}
+	self.keyToVal[key] = value
+	self.valToKey[value] = key
+	return
+}
+
+// PutStrict stores value under key only if neither key nor value are
+// already bound. Otherwise the map is not modified and [ErrKeyExists],
+// [ErrValueExists] or both joined are returned.
+//
+// Arguments:
+//   - key: The key to store the value under.
+//   - value: The value to store.
+//
+// Returns:
+//   - err: nil if the pair was stored, otherwise an error that matches the
+//     conflicting sides with errors.Is.
+//
+// Example:
+//
+//	m := NewMap[int, string]()
+//	err := m.PutStrict(1, "a") // err == nil
+//	err = m.PutStrict(1, "b")  // errors.Is(err, ErrKeyExists)
+//	err = m.PutStrict(2, "a")  // errors.Is(err, ErrValueExists)
+func (self *Map[K, V]) PutStrict(key K, value V) (err error) {
+	var keyErr, valErr error
+	if _, exists := self.keyToVal[key]; exists {
+		keyErr = ErrKeyExists
+	}
+	if _, exists := self.valToKey[value]; exists {
+		valErr = ErrValueExists
+	}
+	if err = errors.Join(keyErr, valErr); err != nil {
+		return
+	}
+	self.keyToVal[key] = value
+	self.valToKey[value] = key
+	return nil
+}
+
+// TryPut stores value under key only if neither key nor value are already
+// bound and returns truth if the pair was stored.
+//
+// Arguments:
+//   - key: The key to store the value under.
+//   - value: The value to store.
+//
+// Returns:
+//   - ok: True if the pair was stored, false if key or value exist.
+//
+// Example:
+//
+//	m := NewMap[int, string]()
+//	ok := m.TryPut(1, "a") // ok == true
+//	ok = m.TryPut(2, "a")  // ok == false
+func (self *Map[K, V]) TryPut(key K, value V) (ok bool) {
+	return self.PutStrict(key, value) == nil
+}
+
+// PutReplace stores value under key, evicting the pair that held key and
+// the pair that held value, and returns the evicted pairs.
+//
+// Arguments:
+//   - key: The key to store the value under.
+//   - value: The value to store.
+//
+// Returns:
+//   - evicted: The pairs removed to store the new pair, at most two, the
+//     pair that held key first. Empty if there were no conflicts or the
+//     pair was already stored.
+//
+// Example:
+//
+//	m := NewMap[int, string]()
+//	m.Put(1, "a")
+//	m.Put(2, "b")
+//	evicted := m.PutReplace(1, "b") // evicted == []Pair{{1, "a"}, {2, "b"}}
+func (self *Map[K, V]) PutReplace(key K, value V) (evicted []Pair[K, V]) {
+	oldValue, keyFound := self.keyToVal[key]
+	oldKey, valueFound := self.valToKey[value]
+	if keyFound && valueFound && oldKey == key {
+		return nil
+	}
+	if keyFound {
+		evicted = append(evicted, Pair[K, V]{key, oldValue})
+		delete(self.valToKey, oldValue)
+	}
+	if valueFound {
+		evicted = append(evicted, Pair[K, V]{oldKey, value})
+		delete(self.keyToVal, oldKey)
+	}
 	self.keyToVal[key] = value
 	self.valToKey[value] = key
-	return self.z, false
+	return
 }
 
 // DeleteByKey deletes an entry by key and returns value that was bound to that
@@ -163,7 +293,7 @@ func (self *Map[K]) Put(key K, value K) (oldValue K, found bool) {
 //
 // Returns:
 //   - deletedValue: The value that was stored under the key, or the zero
-//     value of K if the key was not found.
+//     value of V if the key was not found.
 //   - exists: True if the key was found and the entry was deleted, false
 //     otherwise.
 //
@@ -173,9 +303,9 @@ func (self *Map[K]) Put(key K, value K) (oldValue K, found bool) {
 //	m.Put("key1", "value1")
 //	deletedValue, exists := m.DeleteByKey("key1") // deletedValue == "value1", exists == true
 //	deletedValue, exists = m.DeleteByKey("key2") // deletedValue == "", exists == false
-func (self *Map[K]) DeleteByKey(key K) (deletedValue K, exists bool) {
+func (self *Map[K, V]) DeleteByKey(key K) (deletedValue V, exists bool) {
 	if deletedValue, exists = self.keyToVal[key]; !exists {
-		return self.z, false
+		return self.zv, false
 	}
 	delete(self.valToKey, deletedValue)
 	delete(self.keyToVal, key)
@@ -200,9 +330,9 @@ func (self *Map[K]) DeleteByKey(key K) (deletedValue K, exists bool) {
 //	m.Put("key1", "value1")
 //	deletedKey, exists := m.DeleteByValue("value1") // deletedKey == "key1", exists == true
 //	deletedKey, exists = m.DeleteByValue("value2") // deletedKey == "", exists == false
-func (self *Map[K]) DeleteByValue(value K) (deletedKey K, exists bool) {
+func (self *Map[K, V]) DeleteByValue(value V) (deletedKey K, exists bool) {
 	if deletedKey, exists = self.valToKey[value]; !exists {
-		return self.z, false
+		return self.zk, false
 	}
 	delete(self.keyToVal, deletedKey)
 	delete(self.valToKey, value)
@@ -225,7 +355,7 @@ func (self *Map[K]) DeleteByValue(value K) (deletedKey K, exists bool) {
 //		keys = append(keys, key)
 //		return true
 //	})
-func (self *Map[K]) EnumKeys(f func(key K) bool) {
+func (self *Map[K, V]) EnumKeys(f func(key K) bool) {
 	for k := range self.keyToVal {
 		if !f(k) {
 			break
@@ -249,7 +379,7 @@ func (self *Map[K]) EnumKeys(f func(key K) bool) {
 //		values = append(values, value)
 //		return true
 //	})
-func (self *Map[K]) EnumValues(f func(value K) bool) {
+func (self *Map[K, V]) EnumValues(f func(value V) bool) {
 	for v := range self.valToKey {
 		if !f(v) {
 			break
@@ -269,7 +399,7 @@ func (self *Map[K]) EnumValues(f func(value K) bool) {
 //	m.Put("key1", "value1")
 //	m.Put("key2", "value2")
 //	keys := m.Keys() // keys == []string{"value1", "value2"} (order not guaranteed)
-func (self *Map[K]) Keys() (out []K) {
+func (self *Map[K, V]) Keys() (out []K) {
 	out = make([]K, 0, len(self.keyToVal))
 	for k := range self.keyToVal {
 		out = append(out, k)
@@ -289,8 +419,8 @@ func (self *Map[K]) Keys() (out []K) {
 //	m.Put("key1", "value1")
 //	m.Put("key2", "value2")
 //	values := m.Values() // values == []string{"key1", "key2"} (order not guaranteed)
-func (self *Map[K]) Values() (out []K) {
-	out = make([]K, 0, len(self.valToKey))
+func (self *Map[K, V]) Values() (out []V) {
+	out = make([]V, 0, len(self.valToKey))
 	for v := range self.valToKey {
 		out = append(out, v)
 	}
@@ -306,23 +436,31 @@ func (self *Map[K]) Values() (out []K) {
 //	m := NewSync[string]()
 //	go m.Put("key1", "value1")
 //	go m.Val("key1")
-type SyncMap[K comparable] struct {
+type SyncMap[K, V comparable] struct {
 	mu sync.Mutex
-	m  *Map[K]
+	m  *Map[K, V]
 }
 
-// NewSync returns a new [SyncMap].
+// NewSyncMap returns a new [SyncMap] of keys K and values V.
 //
 // Example:
 //
-//	m := NewSync[string]()
-//	m.Put("key1", "value1")
-func NewSync[K comparable]() *SyncMap[K] {
-	return &SyncMap[K]{
-		m: New[K](),
+//	m := NewSyncMap[int64, string]()
+//	m.Put(1, "alice")
+func NewSyncMap[K, V comparable]() *SyncMap[K, V] {
+	return &SyncMap[K, V]{
+		m: NewMap[K, V](),
 	}
 }
 
+// NewSync returns a new [SyncMap] whose keys and values are both of type K.
+//
+// Example:
+//
+//	m := NewSync[string]()
+//	m.Put("key1", "value1")
+func NewSync[K comparable]() *SyncMap[K, K] { return NewSyncMap[K, K]() }
+
 // Len returns number of entries in the map.
 //
 // Example:
@@ -331,7 +469,7 @@ func NewSync[K comparable]() *SyncMap[K] {
 //	m.Put("key1", "value1")
 //	m.Put("key2", "value2")
 //	l := m.Len() // l == 2
-func (self *SyncMap[K]) Len() (out int) {
+func (self *SyncMap[K, V]) Len() (out int) {
 	self.mu.Lock()
 	out = self.m.Len()
 	self.mu.Unlock()
@@ -352,17 +490,17 @@ func (self *SyncMap[K]) Len() (out int) {
 //	m.Put("key1", "value1")
 //	exists := m.KeyExists("key1") // exists == true
 //	exists := m.KeyExists("key2") // exists == false
-func (self *SyncMap[K]) KeyExists(k K) (b bool) {
+func (self *SyncMap[K, V]) KeyExists(k K) (b bool) {
 	self.mu.Lock()
 	b = self.m.KeyExists(k)
 	self.mu.Unlock()
 	return
 }
 
-// ValExists returns truth if an entry under value k exists.
+// ValExists returns truth if an entry under value v exists.
 //
 // Arguments:
-//   - k: The value to check for existence.
+//   - v: The value to check for existence.
 //
 // Returns:
 //   - b: True if the value exists in the map, false otherwise.
@@ -373,9 +511,9 @@ func (self *SyncMap[K]) KeyExists(k K) (b bool) {
 //	m.Put("key1", "value1")
 //	exists := m.ValExists("value1") // exists == true
 //	exists := m.ValExists("value2") // exists == false
-func (self *SyncMap[K]) ValExists(k K) (b bool) {
+func (self *SyncMap[K, V]) ValExists(v V) (b bool) {
 	self.mu.Lock()
-	b = self.m.ValExists(k)
+	b = self.m.ValExists(v)
 	self.mu.Unlock()
 	return
 }
@@ -397,7 +535,7 @@ func (self *SyncMap[K]) ValExists(k K) (b bool) {
 //	m.Put("key1", "value1")
 //	key, ok := m.Key("value1") // key == "key1", ok == true
 //	key, ok := m.Key("value2") // key == "", ok == false
-func (self *SyncMap[K]) Key(value K) (k K, b bool) {
+func (self *SyncMap[K, V]) Key(value V) (k K, b bool) {
 	self.mu.Lock()
 	k, b = self.m.Key(value)
 	self.mu.Unlock()
@@ -412,7 +550,7 @@ func (self *SyncMap[K]) Key(value K) (k K, b bool) {
 //
 // Returns:
 //   - v: The value associated with the key. If the key is not found, the
-//     zero value of K is returned.
+//     zero value of V is returned.
 //   - b: True if the key was found, false otherwise.
 //
 // Example:
@@ -421,7 +559,7 @@ func (self *SyncMap[K]) Key(value K) (k K, b bool) {
 //	m.Put("key1", "value1")
 //	value, ok := m.Val("key1") // value == "value1", ok == true
 //	value, ok := m.Val("key2") // value == "", ok == false
-func (self *SyncMap[K]) Val(key K) (v K, b bool) {
+func (self *SyncMap[K, V]) Val(key K) (v V, b bool) {
 	self.mu.Lock()
 	v, b = self.m.Val(key)
 	self.mu.Unlock()
@@ -429,7 +567,8 @@ func (self *SyncMap[K]) Val(key K) (v K, b bool) {
 }
 
 // Put stores value v under key k and returns a value that was replaced and a
-// truth if value existed under key k and was replaced.
+// truth if value existed under key k and was replaced. If v was bound to
+// another key that pair is removed.
 //
 // Arguments:
 //   - k: The key to store the value under.
@@ -437,7 +576,7 @@ func (self *SyncMap[K]) Val(key K) (v K, b bool) {
 //
 // Returns:
 //   - old: The value that was previously stored under the key, or the
-//     zero value of K if no value was previously stored.
+//     zero value of V if no value was previously stored.
 //   - found: True if a value was previously stored under the key and was
 //     replaced, false otherwise.
 //
@@ -446,13 +585,82 @@ func (self *SyncMap[K]) Val(key K) (v K, b bool) {
 //	m := NewSync[string]()
 //	oldValue, found := m.Put("key1", "value1") // oldValue == "", found == false
 //	oldValue, found = m.Put("key1", "value2") // oldValue == "value1", found == true
-func (self *SyncMap[K]) Put(k K, v K) (old K, found bool) {
+func (self *SyncMap[K, V]) Put(k K, v V) (old V, found bool) {
 	self.mu.Lock()
 	old, found = self.m.Put(k, v)
 	self.mu.Unlock()
 	return
 }
 
+// PutStrict stores value under key only if neither key nor value are
+// already bound. Otherwise the map is not modified and [ErrKeyExists],
+// [ErrValueExists] or both joined are returned.
+//
+// Arguments:
+//   - key: The key to store the value under.
+//   - value: The value to store.
+//
+// Returns:
+//   - err: nil if the pair was stored, otherwise an error that matches the
+//     conflicting sides with errors.Is.
+//
+// Example:
+//
+//	m := NewSyncMap[int, string]()
+//	err := m.PutStrict(1, "a") // err == nil
+//	err = m.PutStrict(1, "b")  // errors.Is(err, ErrKeyExists)
+func (self *SyncMap[K, V]) PutStrict(key K, value V) (err error) {
+	self.mu.Lock()
+	err = self.m.PutStrict(key, value)
+	self.mu.Unlock()
+	return
+}
+
+// TryPut stores value under key only if neither key nor value are already
+// bound and returns truth if the pair was stored.
+//
+// Arguments:
+//   - key: The key to store the value under.
+//   - value: The value to store.
+//
+// Returns:
+//   - ok: True if the pair was stored, false if key or value exist.
+//
+// Example:
+//
+//	m := NewSyncMap[int, string]()
+//	ok := m.TryPut(1, "a") // ok == true
+//	ok = m.TryPut(2, "a")  // ok == false
+func (self *SyncMap[K, V]) TryPut(key K, value V) (ok bool) {
+	self.mu.Lock()
+	ok = self.m.TryPut(key, value)
+	self.mu.Unlock()
+	return
+}
+
+// PutReplace stores value under key, evicting the pair that held key and
+// the pair that held value, and returns the evicted pairs.
+//
+// Arguments:
+//   - key: The key to store the value under.
+//   - value: The value to store.
+//
+// Returns:
+//   - evicted: The pairs removed to store the new pair, at most two, the
+//     pair that held key first.
+//
+// Example:
+//
+//	m := NewSyncMap[int, string]()
+//	m.Put(1, "a")
+//	evicted := m.PutReplace(2, "a") // evicted == []Pair{{1, "a"}}
+func (self *SyncMap[K, V]) PutReplace(key K, value V) (evicted []Pair[K, V]) {
+	self.mu.Lock()
+	evicted = self.m.PutReplace(key, value)
+	self.mu.Unlock()
+	return
+}
+
 // DeleteByKey deletes an entry by key and returns value that was bound to that
 // key and truth if item was found and deleted.
 //
@@ -461,7 +669,7 @@ func (self *SyncMap[K]) Put(k K, v K) (old K, found bool) {
 //
 // Returns:
 //   - deletedValue: The value that was stored under the key, or the zero
-//     value of K if the key was not found.
+//     value of V if the key was not found.
 //   - exists: True if the key was found and the entry was deleted, false
 //     otherwise.
 //
@@ -471,7 +679,7 @@ func (self *SyncMap[K]) Put(k K, v K) (old K, found bool) {
 //	m.Put("key1", "value1")
 //	deletedValue, exists := m.DeleteByKey("key1") // deletedValue == "value1", exists == true
 //	deletedValue, exists = m.DeleteByKey("key2") // deletedValue == "", exists == false
-func (self *SyncMap[K]) DeleteByKey(key K) (deletedValue K, exists bool) {
+func (self *SyncMap[K, V]) DeleteByKey(key K) (deletedValue V, exists bool) {
 	self.mu.Lock()
 	deletedValue, exists = self.m.DeleteByKey(key)
 	self.mu.Unlock()
@@ -496,7 +704,7 @@ func (self *SyncMap[K]) DeleteByKey(key K) (deletedValue K, exists bool) {
 //	m.Put("key1", "value1")
 //	deletedKey, exists := m.DeleteByValue("value1") // deletedKey == "key1", exists == true
 //	deletedKey, exists = m.DeleteByValue("value2") // deletedKey == "", exists == false
-func (self *SyncMap[K]) DeleteByValue(value K) (deletedKey K, exists bool) {
+func (self *SyncMap[K, V]) DeleteByValue(value V) (deletedKey K, exists bool) {
 	self.mu.Lock()
 	deletedKey, exists = self.m.DeleteByValue(value)
 	self.mu.Unlock()
@@ -519,7 +727,7 @@ func (self *SyncMap[K]) DeleteByValue(value K) (deletedKey K, exists bool) {
 //		keys = append(keys, key)
 //		return true
 //	})
-func (self *SyncMap[K]) EnumKeys(f func(key K) bool) {
+func (self *SyncMap[K, V]) EnumKeys(f func(key K) bool) {
 	self.mu.Lock()
 	self.m.EnumKeys(f)
 	self.mu.Unlock()
@@ -542,7 +750,7 @@ func (self *SyncMap[K]) EnumKeys(f func(key K) bool) {
 //		values = append(values, value)
 //		return true
 //	})
-func (self *SyncMap[K]) EnumValues(f func(value K) bool) {
+func (self *SyncMap[K, V]) EnumValues(f func(value V) bool) {
 	self.mu.Lock()
 	self.m.EnumValues(f)
 	self.mu.Unlock()
@@ -561,7 +769,7 @@ func (self *SyncMap[K]) EnumValues(f func(value K) bool) {
 //	m.Put("key1", "value1")
 //	m.Put("key2", "value2")
 //	keys := m.Keys() // keys == []string{"value1", "value2"} (order not guaranteed)
-func (self *SyncMap[K]) Keys() (out []K) {
+func (self *SyncMap[K, V]) Keys() (out []K) {
 	self.mu.Lock()
 	out = self.m.Keys()
 	self.mu.Unlock()
@@ -580,7 +788,7 @@ func (self *SyncMap[K]) Keys() (out []K) {
 //	m.Put("key1", "value1")
 //	m.Put("key2", "value2")
 //	values := m.Values() // values == []string{"key1", "key2"} (order not guaranteed)
-func (self *SyncMap[K]) Values() (out []K) {
+func (self *SyncMap[K, V]) Values() (out []V) {
 	self.mu.Lock()
 	out = self.m.Values()
 	self.mu.Unlock()
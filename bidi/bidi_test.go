@@ -1,7 +1,9 @@
 package bidi
 
 import (
+	"errors"
 	"math/rand"
+	"slices"
 	"sync"
 	"testing"
 )
@@ -89,6 +91,102 @@ func TestBidiMap(t *testing.T) {
 	}
 }
 
+func TestBidiMapTwoTypes(t *testing.T) {
+	m := NewMap[int64, string]()
+	m.Put(1, "alice")
+	m.Put(2, "bob")
+
+	if name, ok := m.Val(1); !ok || name != "alice" {
+		t.Errorf("Val(1) should be (alice, true), got (%s, %t)", name, ok)
+	}
+	if id, ok := m.Key("bob"); !ok || id != 2 {
+		t.Errorf("Key(bob) should be (2, true), got (%d, %t)", id, ok)
+	}
+	if id, ok := m.DeleteByValue("alice"); !ok || id != 1 {
+		t.Errorf("DeleteByValue(alice) should be (1, true), got (%d, %t)", id, ok)
+	}
+	if name, ok := m.DeleteByKey(3); ok || name != "" {
+		t.Errorf("DeleteByKey(3) should be (\"\", false), got (%s, %t)", name, ok)
+	}
+	var values []string = m.Values()
+	if len(values) != 1 || values[0] != "bob" {
+		t.Errorf("Values() should be [bob], got %v", values)
+	}
+
+	s := NewSyncMap[int64, string]()
+	s.Put(1, "alice")
+	if id, ok := s.Key("alice"); !ok || id != 1 {
+		t.Errorf("Key(alice) should be (1, true), got (%d, %t)", id, ok)
+	}
+}
+
+func TestBidiMapConflicts(t *testing.T) {
+	t.Run("Put", func(t *testing.T) {
+		m := NewMap[int, string]()
+		m.Put(1, "a")
+		m.Put(2, "b")
+		if old, found := m.Put(1, "c"); !found || old != "a" {
+			t.Errorf("Put(1, c) should be (a, true), got (%s, %t)", old, found)
+		}
+		// "b" moves from key 2 to key 1.
+		m.Put(1, "b")
+		if m.Len() != 1 || m.KeyExists(2) || m.ValExists("c") {
+			t.Errorf("Put should keep the map one-to-one, keys %v values %v", m.Keys(), m.Values())
+		}
+	})
+
+	t.Run("PutStrict", func(t *testing.T) {
+		m := NewMap[int, string]()
+		if err := m.PutStrict(1, "a"); err != nil {
+			t.Fatal(err)
+		}
+		m.Put(2, "b")
+		if err := m.PutStrict(1, "x"); !errors.Is(err, ErrKeyExists) || errors.Is(err, ErrValueExists) {
+			t.Errorf("PutStrict(1, x) should fail with ErrKeyExists, got %v", err)
+		}
+		if err := m.PutStrict(3, "a"); !errors.Is(err, ErrValueExists) || errors.Is(err, ErrKeyExists) {
+			t.Errorf("PutStrict(3, a) should fail with ErrValueExists, got %v", err)
+		}
+		if err := m.PutStrict(1, "b"); !errors.Is(err, ErrKeyExists) || !errors.Is(err, ErrValueExists) {
+			t.Errorf("PutStrict(1, b) should fail with both errors, got %v", err)
+		}
+		if v, _ := m.Val(1); v != "a" || m.Len() != 2 {
+			t.Errorf("PutStrict should not modify the map on conflict")
+		}
+	})
+
+	t.Run("TryPut", func(t *testing.T) {
+		m := NewSyncMap[int, string]()
+		if !m.TryPut(1, "a") {
+			t.Error("TryPut(1, a) should succeed")
+		}
+		if m.TryPut(2, "a") || m.TryPut(1, "b") {
+			t.Error("TryPut should fail on conflict")
+		}
+	})
+
+	t.Run("PutReplace", func(t *testing.T) {
+		m := NewMap[int, string]()
+		m.Put(1, "a")
+		m.Put(2, "b")
+		evicted := m.PutReplace(1, "b")
+		if want := []Pair[int, string]{{1, "a"}, {2, "b"}}; !slices.Equal(evicted, want) {
+			t.Errorf("PutReplace(1, b) should evict %v, got %v", want, evicted)
+		}
+		if m.Len() != 1 {
+			t.Errorf("Len() should be 1, got %d", m.Len())
+		}
+		if evicted := m.PutReplace(1, "b"); len(evicted) != 0 {
+			t.Errorf("PutReplace of existing pair should evict nothing, got %v", evicted)
+		}
+		s := NewSyncMap[int, string]()
+		s.Put(1, "a")
+		if evicted := s.PutReplace(2, "a"); !slices.Equal(evicted, []Pair[int, string]{{1, "a"}}) {
+			t.Errorf("PutReplace(2, a) should evict [{1 a}], got %v", evicted)
+		}
+	})
+}
+
 func TestSyncBidiMap(t *testing.T) {
 	m := NewSync[int]()
 
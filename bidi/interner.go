@@ -0,0 +1,244 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bidi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"sync"
+)
+
+// ErrCorrupt is returned by [Interner.Load] when the input is not valid
+// interner data.
+var ErrCorrupt = errors.New("corrupt interner data")
+
+const (
+	// internerMagic starts the binary interner format.
+	internerMagic = "BINT"
+	// internerVersion is the version of the binary interner format.
+	internerVersion = 1
+	// maxInternedLen limits the length of a loaded string.
+	maxInternedLen = 1 << 30
+)
+
+// Interner assigns dense sequential uint32 IDs to strings, starting at 0.
+//
+// It allows looking up the string of an ID and the ID of a string while
+// storing each string once, shared by a slice indexed by ID and a map of
+// strings to IDs. IDs are never reused or reassigned so they can be stored
+// in place of strings and made stable across runs with [Interner.Save] and
+// [Interner.Load].
+//
+// Interner is safe for concurrent use.
+//
+// Example:
+//
+//	in := NewInterner()
+//	id := in.Intern("alpha")  // id == 0
+//	id = in.Intern("beta")    // id == 1
+//	id = in.Intern("alpha")   // id == 0
+//	s, ok := in.String(1)     // s == "beta", ok == true
+type Interner struct {
+	mu   sync.RWMutex
+	strs []string
+	ids  map[string]uint32
+}
+
+// NewInterner returns a new empty [Interner].
+func NewInterner() *Interner {
+	return &Interner{ids: make(map[string]uint32)}
+}
+
+// Len returns number of interned strings.
+func (self *Interner) Len() (out int) {
+	self.mu.RLock()
+	out = len(self.strs)
+	self.mu.RUnlock()
+	return
+}
+
+// Intern returns the ID of s, assigning the next ID if s was not interned
+// before. It panics if all uint32 IDs are in use.
+//
+// Example:
+//
+//	in := NewInterner()
+//	id := in.Intern("alpha") // id == 0
+func (self *Interner) Intern(s string) (id uint32) {
+	var ok bool
+	if id, ok = self.Lookup(s); ok {
+		return
+	}
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if id, ok = self.ids[s]; ok {
+		return
+	}
+	if uint64(len(self.strs)) > math.MaxUint32 {
+		panic("bidi: interner full")
+	}
+	id = uint32(len(self.strs))
+	self.strs = append(self.strs, s)
+	self.ids[s] = id
+	return
+}
+
+// Lookup returns the ID of s and truth if s was interned.
+//
+// Example:
+//
+//	in := NewInterner()
+//	in.Intern("alpha")
+//	id, ok := in.Lookup("alpha") // id == 0, ok == true
+//	id, ok = in.Lookup("beta")   // id == 0, ok == false
+func (self *Interner) Lookup(s string) (id uint32, ok bool) {
+	self.mu.RLock()
+	id, ok = self.ids[s]
+	self.mu.RUnlock()
+	return
+}
+
+// String returns the string of id and truth if id was assigned.
+//
+// Example:
+//
+//	in := NewInterner()
+//	in.Intern("alpha")
+//	s, ok := in.String(0) // s == "alpha", ok == true
+func (self *Interner) String(id uint32) (s string, ok bool) {
+	self.mu.RLock()
+	if ok = uint64(id) < uint64(len(self.strs)); ok {
+		s = self.strs[id]
+	}
+	self.mu.RUnlock()
+	return
+}
+
+// Save writes the interned strings to w in a compact binary format that
+// [Interner.Load] reads back with the same IDs.
+//
+// The format is the magic "BINT", a version byte, the uvarint count of
+// strings followed by each string in ID order as a uvarint length and
+// bytes, and the little endian CRC32 (IEEE) of all preceding bytes.
+//
+// Example:
+//
+//	var buf bytes.Buffer
+//	err := in.Save(&buf)
+func (self *Interner) Save(w io.Writer) (err error) {
+	self.mu.RLock()
+	defer self.mu.RUnlock()
+
+	var (
+		crc = crc32.NewIEEE()
+		bw  = bufio.NewWriter(io.MultiWriter(w, crc))
+		buf [binary.MaxVarintLen64]byte
+	)
+	bw.WriteString(internerMagic)
+	bw.WriteByte(internerVersion)
+	bw.Write(binary.AppendUvarint(buf[:0], uint64(len(self.strs))))
+	for _, s := range self.strs {
+		bw.Write(binary.AppendUvarint(buf[:0], uint64(len(s))))
+		bw.WriteString(s)
+	}
+	if err = bw.Flush(); err != nil {
+		return
+	}
+	binary.LittleEndian.PutUint32(buf[:4], crc.Sum32())
+	_, err = w.Write(buf[:4])
+	return
+}
+
+// Load replaces the interned strings with strings read from r as written
+// by [Interner.Save]. If the input is not valid an error wrapping
+// [ErrCorrupt] is returned and the interner is left unchanged.
+//
+// Example:
+//
+//	in := NewInterner()
+//	err := in.Load(bytes.NewReader(data))
+func (self *Interner) Load(r io.Reader) (err error) {
+	var hr = &hashReader{r: bufio.NewReader(r), h: crc32.NewIEEE()}
+	var header [len(internerMagic) + 1]byte
+	if _, err = io.ReadFull(hr, header[:]); err != nil {
+		return fmt.Errorf("%w: read header: %v", ErrCorrupt, err)
+	}
+	if string(header[:len(internerMagic)]) != internerMagic {
+		return fmt.Errorf("%w: invalid magic", ErrCorrupt)
+	}
+	if header[len(internerMagic)] != internerVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrCorrupt, header[len(internerMagic)])
+	}
+	var count uint64
+	if count, err = binary.ReadUvarint(hr); err != nil {
+		return fmt.Errorf("%w: read count: %v", ErrCorrupt, err)
+	}
+	if count > math.MaxUint32+1 {
+		return fmt.Errorf("%w: invalid count %d", ErrCorrupt, count)
+	}
+	// Do not trust count with a large allocation before strings are read.
+	var (
+		strs = make([]string, 0, min(count, 1<<16))
+		ids  = make(map[string]uint32, min(count, 1<<16))
+	)
+	for id := range count {
+		var n uint64
+		if n, err = binary.ReadUvarint(hr); err != nil {
+			return fmt.Errorf("%w: read string %d: %v", ErrCorrupt, id, err)
+		}
+		if n > maxInternedLen {
+			return fmt.Errorf("%w: string %d too long", ErrCorrupt, id)
+		}
+		var b = make([]byte, n)
+		if _, err = io.ReadFull(hr, b); err != nil {
+			return fmt.Errorf("%w: read string %d: %v", ErrCorrupt, id, err)
+		}
+		var s = string(b)
+		if _, exists := ids[s]; exists {
+			return fmt.Errorf("%w: duplicate string %d", ErrCorrupt, id)
+		}
+		strs = append(strs, s)
+		ids[s] = uint32(id)
+	}
+	var sum = hr.h.Sum32()
+	var trailer [4]byte
+	if _, err = io.ReadFull(hr.r, trailer[:]); err != nil {
+		return fmt.Errorf("%w: read checksum: %v", ErrCorrupt, err)
+	}
+	if binary.LittleEndian.Uint32(trailer[:]) != sum {
+		return fmt.Errorf("%w: checksum mismatch", ErrCorrupt)
+	}
+	self.mu.Lock()
+	self.strs, self.ids = strs, ids
+	self.mu.Unlock()
+	return nil
+}
+
+// hashReader is a reader that writes everything it reads to a hash.
+type hashReader struct {
+	r *bufio.Reader
+	h hash.Hash32
+}
+
+// Read implements io.Reader.
+func (self *hashReader) Read(p []byte) (n int, err error) {
+	n, err = self.r.Read(p)
+	self.h.Write(p[:n])
+	return
+}
+
+// ReadByte implements io.ByteReader.
+func (self *hashReader) ReadByte() (b byte, err error) {
+	if b, err = self.r.ReadByte(); err == nil {
+		self.h.Write([]byte{b})
+	}
+	return
+}
@@ -0,0 +1,111 @@
+package bidi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestInterner(t *testing.T) {
+	in := NewInterner()
+	for i, s := range []string{"alpha", "beta", "", "gamma"} {
+		if id := in.Intern(s); id != uint32(i) {
+			t.Errorf("Intern(%q) should be %d, got %d", s, i, id)
+		}
+	}
+	if id := in.Intern("beta"); id != 1 {
+		t.Errorf("Intern(beta) should be 1, got %d", id)
+	}
+	if in.Len() != 4 {
+		t.Errorf("Len() should be 4, got %d", in.Len())
+	}
+	if id, ok := in.Lookup("gamma"); !ok || id != 3 {
+		t.Errorf("Lookup(gamma) should be (3, true), got (%d, %t)", id, ok)
+	}
+	if _, ok := in.Lookup("delta"); ok {
+		t.Error("Lookup(delta) should fail")
+	}
+	if s, ok := in.String(2); !ok || s != "" {
+		t.Errorf("String(2) should be (\"\", true), got (%q, %t)", s, ok)
+	}
+	if _, ok := in.String(4); ok {
+		t.Error("String(4) should fail")
+	}
+}
+
+func TestInternerConcurrent(t *testing.T) {
+	in := NewInterner()
+	var wg sync.WaitGroup
+	var ids [4][]uint32
+	for g := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				ids[g] = append(ids[g], in.Intern(fmt.Sprint(i)))
+			}
+		}()
+	}
+	wg.Wait()
+	if in.Len() != 200 {
+		t.Fatalf("Len() should be 200, got %d", in.Len())
+	}
+	for g := range ids {
+		for i, id := range ids[g] {
+			if s, _ := in.String(id); s != fmt.Sprint(i) {
+				t.Fatalf("String(%d) should be %d, got %q", id, i, s)
+			}
+		}
+	}
+}
+
+func TestInternerSaveLoad(t *testing.T) {
+	in := NewInterner()
+	for i := range 1000 {
+		in.Intern(fmt.Sprintf("label-%d", i*7))
+	}
+	var buf bytes.Buffer
+	if err := in.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	out := NewInterner()
+	out.Intern("stale")
+	if err := out.Load(bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 1000 {
+		t.Fatalf("Len() should be 1000, got %d", out.Len())
+	}
+	for i := range 1000 {
+		if id, ok := out.Lookup(fmt.Sprintf("label-%d", i*7)); !ok || id != uint32(i) {
+			t.Fatalf("Lookup(label-%d) should be (%d, true), got (%d, %t)", i*7, i, id, ok)
+		}
+	}
+	if _, ok := out.Lookup("stale"); ok {
+		t.Error("Load should replace existing strings")
+	}
+	if id := out.Intern("new"); id != 1000 {
+		t.Errorf("Intern(new) after Load should be 1000, got %d", id)
+	}
+
+	for name, bad := range map[string][]byte{
+		"empty":     nil,
+		"magic":     append([]byte("XINT"), data[4:]...),
+		"truncated": data[:len(data)-10],
+		"flipped":   append(append([]byte{}, data[:20]...), append([]byte{data[20] ^ 1}, data[21:]...)...),
+		"checksum":  append(append([]byte{}, data[:len(data)-1]...), data[len(data)-1]^1),
+	} {
+		out := NewInterner()
+		out.Intern("kept")
+		if err := out.Load(bytes.NewReader(bad)); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("%s: Load should fail with ErrCorrupt, got %v", name, err)
+		}
+		if s, _ := out.String(0); s != "kept" || out.Len() != 1 {
+			t.Errorf("%s: failed Load should leave interner unchanged", name)
+		}
+	}
+}
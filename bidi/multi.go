@@ -0,0 +1,363 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bidi
+
+import "sync"
+
+// MultiMap is an unordered generic bidirectional one-to-many map of keys K
+// and values V.
+//
+// A key holds any number of values and a value belongs to at most one key,
+// e.g. a group has many members and a member belongs to one group. It allows
+// you to look up values by key and the key by value. Putting a value under
+// another key moves it. A key that loses its last value is removed.
+// The zero value of the type is not usable, use [NewMultiMap] to create a
+// new map.
+//
+// Example:
+//
+//	m := NewMultiMap[string, string]()
+//	m.Put("admins", "alice")
+//	m.Put("admins", "bob")
+//	m.Put("users", "carol")
+//	members := m.Values("admins") // members == []string{"alice", "bob"} (order not guaranteed)
+//	group, ok := m.Key("carol")   // group == "users", ok == true
+type MultiMap[K, V comparable] struct {
+	zk        K
+	keyToVals map[K]map[V]struct{}
+	valToKey  map[V]K
+}
+
+// NewMultiMap returns a new [MultiMap] of keys K and values V.
+//
+// Example:
+//
+//	m := NewMultiMap[string, int]()
+func NewMultiMap[K, V comparable]() *MultiMap[K, V] {
+	return &MultiMap[K, V]{
+		zk:        *new(K),
+		keyToVals: make(map[K]map[V]struct{}),
+		valToKey:  make(map[V]K),
+	}
+}
+
+// Len returns number of values in the map.
+func (self *MultiMap[K, V]) Len() int { return len(self.valToKey) }
+
+// KeyLen returns number of keys in the map.
+func (self *MultiMap[K, V]) KeyLen() int { return len(self.keyToVals) }
+
+// Count returns number of values under key.
+func (self *MultiMap[K, V]) Count(key K) int { return len(self.keyToVals[key]) }
+
+// KeyExists returns truth if key holds any values.
+func (self *MultiMap[K, V]) KeyExists(key K) (exists bool) {
+	_, exists = self.keyToVals[key]
+	return
+}
+
+// ValExists returns truth if value exists.
+func (self *MultiMap[K, V]) ValExists(value V) (exists bool) {
+	_, exists = self.valToKey[value]
+	return
+}
+
+// Key returns the key holding value and truth if the value was found.
+//
+// Example:
+//
+//	m := NewMultiMap[string, string]()
+//	m.Put("admins", "alice")
+//	key, ok := m.Key("alice") // key == "admins", ok == true
+func (self *MultiMap[K, V]) Key(value V) (key K, b bool) {
+	key, b = self.valToKey[value]
+	return
+}
+
+// Values returns values under key or nil if key does not exist. The order
+// of the values is not guaranteed.
+//
+// Example:
+//
+//	m := NewMultiMap[string, string]()
+//	m.Put("admins", "alice")
+//	values := m.Values("admins") // values == []string{"alice"}
+func (self *MultiMap[K, V]) Values(key K) (out []V) {
+	var values, ok = self.keyToVals[key]
+	if !ok {
+		return nil
+	}
+	out = make([]V, 0, len(values))
+	for value := range values {
+		out = append(out, value)
+	}
+	return
+}
+
+// Put adds value under key. If value was held by another key it is moved
+// and the previous key is returned as oldKey with moved set to true.
+//
+// Example:
+//
+//	m := NewMultiMap[string, string]()
+//	oldKey, moved := m.Put("admins", "alice") // oldKey == "", moved == false
+//	oldKey, moved = m.Put("users", "alice")   // oldKey == "admins", moved == true
+func (self *MultiMap[K, V]) Put(key K, value V) (oldKey K, moved bool) {
+	var exists bool
+	if oldKey, exists = self.valToKey[value]; exists {
+		if oldKey == key {
+			return self.zk, false
+		}
+		self.unlink(oldKey, value)
+		moved = true
+	}
+	var values, ok = self.keyToVals[key]
+	if !ok {
+		values = make(map[V]struct{})
+		self.keyToVals[key] = values
+	}
+	values[value] = struct{}{}
+	self.valToKey[value] = key
+	return
+}
+
+// Move moves an existing value to newKey and returns truth if the value
+// was found. Unlike [MultiMap.Put] it does not add values that do not exist.
+//
+// Example:
+//
+//	m := NewMultiMap[string, string]()
+//	m.Put("admins", "alice")
+//	ok := m.Move("alice", "users") // ok == true
+//	ok = m.Move("bob", "users")    // ok == false
+func (self *MultiMap[K, V]) Move(value V, newKey K) (ok bool) {
+	if _, ok = self.valToKey[value]; ok {
+		self.Put(newKey, value)
+	}
+	return
+}
+
+// DeleteKey deletes key and all values under it and returns the deleted
+// values or nil if key did not exist.
+//
+// Example:
+//
+//	m := NewMultiMap[string, string]()
+//	m.Put("admins", "alice")
+//	m.Put("admins", "bob")
+//	values := m.DeleteKey("admins") // values == []string{"alice", "bob"} (order not guaranteed)
+func (self *MultiMap[K, V]) DeleteKey(key K) (deletedValues []V) {
+	if deletedValues = self.Values(key); deletedValues == nil {
+		return
+	}
+	for _, value := range deletedValues {
+		delete(self.valToKey, value)
+	}
+	delete(self.keyToVals, key)
+	return
+}
+
+// DeleteValue deletes value and returns the key that held it and truth if
+// the value was found. If the key holds no more values it is removed.
+//
+// Example:
+//
+//	m := NewMultiMap[string, string]()
+//	m.Put("admins", "alice")
+//	key, ok := m.DeleteValue("alice") // key == "admins", ok == true
+//	ok = m.KeyExists("admins")        // ok == false
+func (self *MultiMap[K, V]) DeleteValue(value V) (deletedKey K, exists bool) {
+	if deletedKey, exists = self.valToKey[value]; !exists {
+		return self.zk, false
+	}
+	self.unlink(deletedKey, value)
+	return
+}
+
+// EnumKeys calls f for each key until all keys have been enumerated or f
+// returns false. The order of the keys is not guaranteed.
+func (self *MultiMap[K, V]) EnumKeys(f func(key K) bool) {
+	for key := range self.keyToVals {
+		if !f(key) {
+			break
+		}
+	}
+}
+
+// EnumValues calls f for each value under key until all values have been
+// enumerated or f returns false. The order of the values is not guaranteed.
+//
+// Example:
+//
+//	m := NewMultiMap[string, string]()
+//	m.Put("admins", "alice")
+//	m.EnumValues("admins", func(value string) bool {
+//		fmt.Println(value) // Prints "alice".
+//		return true
+//	})
+func (self *MultiMap[K, V]) EnumValues(key K, f func(value V) bool) {
+	for value := range self.keyToVals[key] {
+		if !f(value) {
+			break
+		}
+	}
+}
+
+// Keys returns all keys. The order of the keys is not guaranteed.
+func (self *MultiMap[K, V]) Keys() (out []K) {
+	out = make([]K, 0, len(self.keyToVals))
+	for key := range self.keyToVals {
+		out = append(out, key)
+	}
+	return
+}
+
+// unlink removes value from key, removing key if it holds no more values.
+func (self *MultiMap[K, V]) unlink(key K, value V) {
+	var values = self.keyToVals[key]
+	delete(values, value)
+	if len(values) == 0 {
+		delete(self.keyToVals, key)
+	}
+	delete(self.valToKey, value)
+}
+
+// SyncMultiMap is the concurrency safe version of [MultiMap].
+//
+// Example:
+//
+//	m := NewSyncMultiMap[string, string]()
+//	go m.Put("admins", "alice")
+//	go m.Key("alice")
+type SyncMultiMap[K, V comparable] struct {
+	mu sync.Mutex
+	m  *MultiMap[K, V]
+}
+
+// NewSyncMultiMap returns a new [SyncMultiMap] of keys K and values V.
+func NewSyncMultiMap[K, V comparable]() *SyncMultiMap[K, V] {
+	return &SyncMultiMap[K, V]{
+		m: NewMultiMap[K, V](),
+	}
+}
+
+// Len returns number of values in the map.
+func (self *SyncMultiMap[K, V]) Len() (out int) {
+	self.mu.Lock()
+	out = self.m.Len()
+	self.mu.Unlock()
+	return
+}
+
+// KeyLen returns number of keys in the map.
+func (self *SyncMultiMap[K, V]) KeyLen() (out int) {
+	self.mu.Lock()
+	out = self.m.KeyLen()
+	self.mu.Unlock()
+	return
+}
+
+// Count returns number of values under key.
+func (self *SyncMultiMap[K, V]) Count(key K) (out int) {
+	self.mu.Lock()
+	out = self.m.Count(key)
+	self.mu.Unlock()
+	return
+}
+
+// KeyExists returns truth if key holds any values.
+func (self *SyncMultiMap[K, V]) KeyExists(key K) (exists bool) {
+	self.mu.Lock()
+	exists = self.m.KeyExists(key)
+	self.mu.Unlock()
+	return
+}
+
+// ValExists returns truth if value exists.
+func (self *SyncMultiMap[K, V]) ValExists(value V) (exists bool) {
+	self.mu.Lock()
+	exists = self.m.ValExists(value)
+	self.mu.Unlock()
+	return
+}
+
+// Key returns the key holding value and truth if the value was found.
+func (self *SyncMultiMap[K, V]) Key(value V) (key K, b bool) {
+	self.mu.Lock()
+	key, b = self.m.Key(value)
+	self.mu.Unlock()
+	return
+}
+
+// Values returns values under key or nil if key does not exist. The order
+// of the values is not guaranteed.
+func (self *SyncMultiMap[K, V]) Values(key K) (out []V) {
+	self.mu.Lock()
+	out = self.m.Values(key)
+	self.mu.Unlock()
+	return
+}
+
+// Put adds value under key. If value was held by another key it is moved
+// and the previous key is returned as oldKey with moved set to true.
+func (self *SyncMultiMap[K, V]) Put(key K, value V) (oldKey K, moved bool) {
+	self.mu.Lock()
+	oldKey, moved = self.m.Put(key, value)
+	self.mu.Unlock()
+	return
+}
+
+// Move moves an existing value to newKey and returns truth if the value
+// was found.
+func (self *SyncMultiMap[K, V]) Move(value V, newKey K) (ok bool) {
+	self.mu.Lock()
+	ok = self.m.Move(value, newKey)
+	self.mu.Unlock()
+	return
+}
+
+// DeleteKey deletes key and all values under it and returns the deleted
+// values or nil if key did not exist.
+func (self *SyncMultiMap[K, V]) DeleteKey(key K) (deletedValues []V) {
+	self.mu.Lock()
+	deletedValues = self.m.DeleteKey(key)
+	self.mu.Unlock()
+	return
+}
+
+// DeleteValue deletes value and returns the key that held it and truth if
+// the value was found.
+func (self *SyncMultiMap[K, V]) DeleteValue(value V) (deletedKey K, exists bool) {
+	self.mu.Lock()
+	deletedKey, exists = self.m.DeleteValue(value)
+	self.mu.Unlock()
+	return
+}
+
+// EnumKeys calls f for each key until all keys have been enumerated or f
+// returns false. The map is locked during enumeration so f must not call
+// methods of the map.
+func (self *SyncMultiMap[K, V]) EnumKeys(f func(key K) bool) {
+	self.mu.Lock()
+	self.m.EnumKeys(f)
+	self.mu.Unlock()
+}
+
+// EnumValues calls f for each value under key until all values have been
+// enumerated or f returns false. The map is locked during enumeration so f
+// must not call methods of the map.
+func (self *SyncMultiMap[K, V]) EnumValues(key K, f func(value V) bool) {
+	self.mu.Lock()
+	self.m.EnumValues(key, f)
+	self.mu.Unlock()
+}
+
+// Keys returns all keys. The order of the keys is not guaranteed.
+func (self *SyncMultiMap[K, V]) Keys() (out []K) {
+	self.mu.Lock()
+	out = self.m.Keys()
+	self.mu.Unlock()
+	return
+}
@@ -0,0 +1,122 @@
+package bidi
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func sorted[T int | string](s []T) []T {
+	slices.Sort(s)
+	return s
+}
+
+func TestMultiMap(t *testing.T) {
+	m := NewMultiMap[string, string]()
+	m.Put("admins", "alice")
+	m.Put("admins", "bob")
+	m.Put("users", "carol")
+
+	if m.Len() != 3 || m.KeyLen() != 2 {
+		t.Errorf("Len(), KeyLen() should be 3, 2, got %d, %d", m.Len(), m.KeyLen())
+	}
+	if values := sorted(m.Values("admins")); !slices.Equal(values, []string{"alice", "bob"}) {
+		t.Errorf("Values(admins) should be [alice bob], got %v", values)
+	}
+	if values := m.Values("nobody"); values != nil {
+		t.Errorf("Values(nobody) should be nil, got %v", values)
+	}
+	if key, ok := m.Key("carol"); !ok || key != "users" {
+		t.Errorf("Key(carol) should be (users, true), got (%s, %t)", key, ok)
+	}
+
+	// Putting the same pair again is a no-op.
+	if _, moved := m.Put("admins", "alice"); moved || m.Count("admins") != 2 {
+		t.Error("Put of existing pair should not move")
+	}
+
+	// Putting a value under another key moves it.
+	if oldKey, moved := m.Put("users", "bob"); !moved || oldKey != "admins" {
+		t.Errorf("Put(users, bob) should be (admins, true), got (%s, %t)", oldKey, moved)
+	}
+	if m.Count("admins") != 1 || m.Count("users") != 2 {
+		t.Errorf("Count should be 1, 2, got %d, %d", m.Count("admins"), m.Count("users"))
+	}
+
+	// Moving the last value removes the key.
+	if !m.Move("alice", "users") {
+		t.Error("Move(alice, users) should succeed")
+	}
+	if m.KeyExists("admins") {
+		t.Error("KeyExists(admins) should be false")
+	}
+	if m.Move("dave", "users") || m.ValExists("dave") {
+		t.Error("Move of missing value should fail")
+	}
+
+	if key, ok := m.DeleteValue("carol"); !ok || key != "users" {
+		t.Errorf("DeleteValue(carol) should be (users, true), got (%s, %t)", key, ok)
+	}
+	if _, ok := m.DeleteValue("carol"); ok {
+		t.Error("DeleteValue(carol) should fail")
+	}
+	if values := sorted(m.DeleteKey("users")); !slices.Equal(values, []string{"alice", "bob"}) {
+		t.Errorf("DeleteKey(users) should be [alice bob], got %v", values)
+	}
+	if m.Len() != 0 || m.KeyLen() != 0 || m.ValExists("alice") {
+		t.Error("map should be empty")
+	}
+	if m.DeleteKey("users") != nil {
+		t.Error("DeleteKey of missing key should return nil")
+	}
+}
+
+func TestMultiMapEnum(t *testing.T) {
+	m := NewMultiMap[int, int]()
+	for i := range 10 {
+		m.Put(i%3, i)
+	}
+	if keys := sorted(m.Keys()); !slices.Equal(keys, []int{0, 1, 2}) {
+		t.Errorf("Keys() should be [0 1 2], got %v", keys)
+	}
+	var values []int
+	m.EnumValues(0, func(value int) bool {
+		values = append(values, value)
+		return true
+	})
+	if !slices.Equal(sorted(values), []int{0, 3, 6, 9}) {
+		t.Errorf("EnumValues(0) should yield [0 3 6 9], got %v", values)
+	}
+	var n int
+	m.EnumKeys(func(key int) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("EnumKeys should stop after 1, got %d", n)
+	}
+}
+
+func TestSyncMultiMap(t *testing.T) {
+	m := NewSyncMultiMap[int, int]()
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				m.Put(i%(g+1), g*100+i)
+				m.Move(g*100+i, g)
+			}
+		}()
+	}
+	wg.Wait()
+	if m.Len() != 400 {
+		t.Errorf("Len() should be 400, got %d", m.Len())
+	}
+	for g := range 4 {
+		if m.Count(g) != 100 {
+			t.Errorf("Count(%d) should be 100, got %d", g, m.Count(g))
+		}
+	}
+}
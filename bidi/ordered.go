@@ -0,0 +1,334 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bidi
+
+// OrderedMap is a generic bidirectional one-to-one map that maintains the
+// order in which keys were inserted.
+//
+// Like [Map] it allows you to look up values by key and keys by value. Like
+// maps.OrderedMap it allows access by index and enumerates pairs in order.
+// The order can be changed with [OrderedMap.Move] and [OrderedMap.Swap].
+// The zero value of the type is not usable, use [NewOrderedMap] to create a
+// new map.
+//
+// Example:
+//
+//	m := NewOrderedMap[int, string]()
+//	m.Put(1, "one")
+//	m.Put(2, "two")
+//	key, ok := m.Key("two")      // key == 2, ok == true
+//	key, val, ok := m.At(0)      // key == 1, val == "one", ok == true
+//	fmt.Println(m.Values())      // Output: [one two]
+type OrderedMap[K, V comparable] struct {
+	zk       K
+	zv       V
+	index    map[K]int
+	keyToVal map[K]V
+	valToKey map[V]K
+	keys     []K
+}
+
+// NewOrderedMap returns a new [OrderedMap] of keys K and values V.
+//
+// Example:
+//
+//	m := NewOrderedMap[int, string]()
+func NewOrderedMap[K, V comparable]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{
+		zk:       *new(K),
+		zv:       *new(V),
+		index:    make(map[K]int),
+		keyToVal: make(map[K]V),
+		valToKey: make(map[V]K),
+	}
+}
+
+// Len returns number of pairs in the map.
+func (self *OrderedMap[K, V]) Len() int { return len(self.keys) }
+
+// KeyExists returns truth if key exists.
+func (self *OrderedMap[K, V]) KeyExists(key K) (exists bool) {
+	_, exists = self.keyToVal[key]
+	return
+}
+
+// ValExists returns truth if value exists.
+func (self *OrderedMap[K, V]) ValExists(value V) (exists bool) {
+	_, exists = self.valToKey[value]
+	return
+}
+
+// Key returns the key of the value and truth if the value was found.
+//
+// Example:
+//
+//	m := NewOrderedMap[int, string]()
+//	m.Put(1, "one")
+//	key, ok := m.Key("one") // key == 1, ok == true
+func (self *OrderedMap[K, V]) Key(value V) (key K, b bool) {
+	key, b = self.valToKey[value]
+	return
+}
+
+// Val returns the value of the key and truth if the key was found.
+//
+// Example:
+//
+//	m := NewOrderedMap[int, string]()
+//	m.Put(1, "one")
+//	value, ok := m.Val(1) // value == "one", ok == true
+func (self *OrderedMap[K, V]) Val(key K) (value V, b bool) {
+	value, b = self.keyToVal[key]
+	return
+}
+
+// At returns the pair at index and truth if index is valid. If index is out
+// of bounds zero values are returned.
+//
+// Example:
+//
+//	m := NewOrderedMap[int, string]()
+//	m.Put(1, "one")
+//	key, value, ok := m.At(0) // key == 1, value == "one", ok == true
+//	key, value, ok = m.At(1)  // key == 0, value == "", ok == false
+func (self *OrderedMap[K, V]) At(index int) (key K, value V, exists bool) {
+	if index < 0 || index >= len(self.keys) {
+		return self.zk, self.zv, false
+	}
+	key = self.keys[index]
+	return key, self.keyToVal[key], true
+}
+
+// IndexOfKey returns the index of key and truth if the key was found.
+//
+// Example:
+//
+//	m := NewOrderedMap[int, string]()
+//	m.Put(1, "one")
+//	m.Put(2, "two")
+//	index, ok := m.IndexOfKey(2) // index == 1, ok == true
+func (self *OrderedMap[K, V]) IndexOfKey(key K) (index int, exists bool) {
+	if index, exists = self.index[key]; !exists {
+		return -1, false
+	}
+	return
+}
+
+// IndexOfVal returns the index of the pair holding value and truth if the
+// value was found.
+func (self *OrderedMap[K, V]) IndexOfVal(value V) (index int, exists bool) {
+	var key K
+	if key, exists = self.valToKey[value]; !exists {
+		return -1, false
+	}
+	return self.index[key], true
+}
+
+// Put stores value under key and returns oldValue that was replaced and a
+// truth if value existed under key and was replaced. A new key is appended
+// to the end of the order, an existing key keeps its position.
+//
+// If value was bound to another key that pair is removed to keep the map
+// one-to-one.
+//
+// Example:
+//
+//	m := NewOrderedMap[int, string]()
+//	oldValue, found := m.Put(1, "one") // oldValue == "", found == false
+//	oldValue, found = m.Put(1, "uno")  // oldValue == "one", found == true
+func (self *OrderedMap[K, V]) Put(key K, value V) (oldValue V, found bool) {
+	if oldKey, exists := self.valToKey[value]; exists && oldKey != key {
+		self.DeleteByKey(oldKey)
+	}
+	if oldValue, found = self.keyToVal[key]; found {
+		delete(self.valToKey, oldValue)
+	} else {
+		self.keys = append(self.keys, key)
+		self.index[key] = len(self.keys) - 1
+	}
+	self.keyToVal[key] = value
+	self.valToKey[value] = key
+	return
+}
+
+// TryPut appends the pair of key and value only if neither key nor value
+// are already bound and returns truth if the pair was stored.
+//
+// Example:
+//
+//	m := NewOrderedMap[int, string]()
+//	ok := m.TryPut(1, "one") // ok == true
+//	ok = m.TryPut(2, "one")  // ok == false
+func (self *OrderedMap[K, V]) TryPut(key K, value V) (ok bool) {
+	if self.KeyExists(key) || self.ValExists(value) {
+		return false
+	}
+	self.Put(key, value)
+	return true
+}
+
+// DeleteByKey deletes a pair by key and returns value that was bound to that
+// key and truth if the pair was found and deleted.
+//
+// Example:
+//
+//	m := NewOrderedMap[int, string]()
+//	m.Put(1, "one")
+//	value, ok := m.DeleteByKey(1) // value == "one", ok == true
+func (self *OrderedMap[K, V]) DeleteByKey(key K) (deletedValue V, exists bool) {
+	var index int
+	if index, exists = self.index[key]; !exists {
+		return self.zv, false
+	}
+	_, deletedValue, _ = self.DeleteAt(index)
+	return
+}
+
+// DeleteByValue deletes a pair by value and returns key that was bound to
+// that value and truth if the pair was found and deleted.
+//
+// Example:
+//
+//	m := NewOrderedMap[int, string]()
+//	m.Put(1, "one")
+//	key, ok := m.DeleteByValue("one") // key == 1, ok == true
+func (self *OrderedMap[K, V]) DeleteByValue(value V) (deletedKey K, exists bool) {
+	if deletedKey, exists = self.valToKey[value]; !exists {
+		return self.zk, false
+	}
+	self.DeleteAt(self.index[deletedKey])
+	return
+}
+
+// DeleteAt deletes the pair at index and returns it and truth if index was
+// valid.
+//
+// Example:
+//
+//	m := NewOrderedMap[int, string]()
+//	m.Put(1, "one")
+//	key, value, ok := m.DeleteAt(0) // key == 1, value == "one", ok == true
+func (self *OrderedMap[K, V]) DeleteAt(index int) (key K, value V, exists bool) {
+	if key, value, exists = self.At(index); !exists {
+		return
+	}
+	delete(self.index, key)
+	delete(self.keyToVal, key)
+	delete(self.valToKey, value)
+	self.keys = append(self.keys[:index], self.keys[index+1:]...)
+	self.reindex(index, len(self.keys))
+	return
+}
+
+// Move moves the pair at index from to index to, shifting the pairs in
+// between, and returns truth if both indexes were valid.
+//
+// Example:
+//
+//	m := NewOrderedMap[int, string]()
+//	m.Put(1, "one")
+//	m.Put(2, "two")
+//	m.Put(3, "three")
+//	m.Move(2, 0)
+//	fmt.Println(m.Keys()) // Output: [3 1 2]
+func (self *OrderedMap[K, V]) Move(from, to int) (ok bool) {
+	if from < 0 || from >= len(self.keys) || to < 0 || to >= len(self.keys) {
+		return false
+	}
+	var key = self.keys[from]
+	if from < to {
+		copy(self.keys[from:to], self.keys[from+1:to+1])
+		self.keys[to] = key
+		self.reindex(from, to+1)
+	} else {
+		copy(self.keys[to+1:from+1], self.keys[to:from])
+		self.keys[to] = key
+		self.reindex(to, from+1)
+	}
+	return true
+}
+
+// Swap swaps the positions of pairs at indexes i and j and returns truth if
+// both indexes were valid.
+func (self *OrderedMap[K, V]) Swap(i, j int) (ok bool) {
+	if i < 0 || i >= len(self.keys) || j < 0 || j >= len(self.keys) {
+		return false
+	}
+	self.keys[i], self.keys[j] = self.keys[j], self.keys[i]
+	self.index[self.keys[i]] = i
+	self.index[self.keys[j]] = j
+	return true
+}
+
+// Enum calls f for each pair in order until all pairs have been enumerated
+// or f returns false.
+//
+// Example:
+//
+//	m := NewOrderedMap[int, string]()
+//	m.Put(1, "one")
+//	m.Put(2, "two")
+//	m.Enum(func(key int, value string) bool {
+//		fmt.Println(key, value) // Prints "1 one" then "2 two".
+//		return true
+//	})
+func (self *OrderedMap[K, V]) Enum(f func(key K, value V) bool) {
+	for _, key := range self.keys {
+		if !f(key, self.keyToVal[key]) {
+			break
+		}
+	}
+}
+
+// EnumKeys calls f for each key in order until all keys have been
+// enumerated or f returns false.
+func (self *OrderedMap[K, V]) EnumKeys(f func(key K) bool) {
+	for _, key := range self.keys {
+		if !f(key) {
+			break
+		}
+	}
+}
+
+// EnumValues calls f for each value in order until all values have been
+// enumerated or f returns false.
+func (self *OrderedMap[K, V]) EnumValues(f func(value V) bool) {
+	for _, key := range self.keys {
+		if !f(self.keyToVal[key]) {
+			break
+		}
+	}
+}
+
+// Keys returns all keys in order.
+func (self *OrderedMap[K, V]) Keys() (out []K) {
+	out = make([]K, len(self.keys))
+	copy(out, self.keys)
+	return
+}
+
+// Values returns all values in order of their keys.
+func (self *OrderedMap[K, V]) Values() (out []V) {
+	out = make([]V, len(self.keys))
+	for i, key := range self.keys {
+		out[i] = self.keyToVal[key]
+	}
+	return
+}
+
+// Clear removes all pairs from the map.
+func (self *OrderedMap[K, V]) Clear() {
+	self.index = make(map[K]int)
+	self.keyToVal = make(map[K]V)
+	self.valToKey = make(map[V]K)
+	self.keys = nil
+}
+
+// reindex updates the index of keys in range [from, to).
+func (self *OrderedMap[K, V]) reindex(from, to int) {
+	for i := from; i < to; i++ {
+		self.index[self.keys[i]] = i
+	}
+}
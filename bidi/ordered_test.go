@@ -0,0 +1,129 @@
+package bidi
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap[int, string]()
+	m.Put(1, "one")
+	m.Put(2, "two")
+	m.Put(3, "three")
+
+	if keys := m.Keys(); !slices.Equal(keys, []int{1, 2, 3}) {
+		t.Errorf("Keys() should be [1 2 3], got %v", keys)
+	}
+	if values := m.Values(); !slices.Equal(values, []string{"one", "two", "three"}) {
+		t.Errorf("Values() should be [one two three], got %v", values)
+	}
+	if key, ok := m.Key("two"); !ok || key != 2 {
+		t.Errorf("Key(two) should be (2, true), got (%d, %t)", key, ok)
+	}
+	if key, value, ok := m.At(2); !ok || key != 3 || value != "three" {
+		t.Errorf("At(2) should be (3, three, true), got (%d, %s, %t)", key, value, ok)
+	}
+	if _, _, ok := m.At(3); ok {
+		t.Error("At(3) should be out of bounds")
+	}
+
+	// Replacing a value keeps the key position.
+	if old, found := m.Put(2, "dos"); !found || old != "two" {
+		t.Errorf("Put(2, dos) should be (two, true), got (%s, %t)", old, found)
+	}
+	if m.ValExists("two") {
+		t.Error("ValExists(two) should be false")
+	}
+	if index, ok := m.IndexOfVal("dos"); !ok || index != 1 {
+		t.Errorf("IndexOfVal(dos) should be (1, true), got (%d, %t)", index, ok)
+	}
+
+	// Moving a value to another key removes the old pair.
+	m.Put(4, "one")
+	if keys := m.Keys(); !slices.Equal(keys, []int{2, 3, 4}) {
+		t.Errorf("Keys() should be [2 3 4], got %v", keys)
+	}
+	if index, ok := m.IndexOfKey(4); !ok || index != 2 {
+		t.Errorf("IndexOfKey(4) should be (2, true), got (%d, %t)", index, ok)
+	}
+	if m.TryPut(5, "dos") || m.TryPut(2, "five") {
+		t.Error("TryPut should fail on conflict")
+	}
+	if !m.TryPut(5, "five") {
+		t.Error("TryPut(5, five) should succeed")
+	}
+
+	if value, ok := m.DeleteByKey(3); !ok || value != "three" {
+		t.Errorf("DeleteByKey(3) should be (three, true), got (%s, %t)", value, ok)
+	}
+	if key, ok := m.DeleteByValue("dos"); !ok || key != 2 {
+		t.Errorf("DeleteByValue(dos) should be (2, true), got (%d, %t)", key, ok)
+	}
+	if keys := m.Keys(); !slices.Equal(keys, []int{4, 5}) {
+		t.Errorf("Keys() should be [4 5], got %v", keys)
+	}
+	if index, _ := m.IndexOfKey(5); index != 1 {
+		t.Errorf("IndexOfKey(5) should be 1, got %d", index)
+	}
+	if _, ok := m.IndexOfKey(3); ok {
+		t.Error("IndexOfKey(3) should fail")
+	}
+
+	m.Clear()
+	if m.Len() != 0 {
+		t.Errorf("Len() should be 0, got %d", m.Len())
+	}
+}
+
+func TestOrderedMapReorder(t *testing.T) {
+	m := NewOrderedMap[int, string]()
+	for i, s := range []string{"a", "b", "c", "d"} {
+		m.Put(i, s)
+	}
+	checkOrder := func(want ...int) {
+		t.Helper()
+		if keys := m.Keys(); !slices.Equal(keys, want) {
+			t.Fatalf("Keys() should be %v, got %v", want, keys)
+		}
+		for i, key := range want {
+			if index, _ := m.IndexOfKey(key); index != i {
+				t.Fatalf("IndexOfKey(%d) should be %d, got %d", key, i, index)
+			}
+		}
+	}
+
+	m.Move(0, 3)
+	checkOrder(1, 2, 3, 0)
+	m.Move(3, 1)
+	checkOrder(1, 0, 2, 3)
+	m.Swap(0, 3)
+	checkOrder(3, 0, 2, 1)
+	if m.Move(0, 4) || m.Swap(-1, 0) {
+		t.Error("Move and Swap should fail on invalid index")
+	}
+
+	var pairs []string
+	m.Enum(func(key int, value string) bool {
+		pairs = append(pairs, value)
+		return len(pairs) < 3
+	})
+	if !slices.Equal(pairs, []string{"d", "a", "c"}) {
+		t.Errorf("Enum should yield [d a c], got %v", pairs)
+	}
+	var values []string
+	m.EnumValues(func(value string) bool {
+		values = append(values, value)
+		return true
+	})
+	if !slices.Equal(values, []string{"d", "a", "c", "b"}) {
+		t.Errorf("EnumValues should yield [d a c b], got %v", values)
+	}
+	var keys []int
+	m.EnumKeys(func(key int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if !slices.Equal(keys, []int{3, 0, 2, 1}) {
+		t.Errorf("EnumKeys should yield [3 0 2 1], got %v", keys)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package graph
+
+// adjacency is the storage shared by [Graph] and [ValueGraph]. It maps each
+// node to the nodes it links to and the value E of each link. [Graph] uses
+// an empty struct for E so that it stores a plain set of links.
+type adjacency[K comparable, E any] struct {
+	mode Mode
+	// links holds links of every node, including nodes without links.
+	links map[K]map[K]E
+	// inlinks holds reverse links of a directed graph, nil if undirected.
+	inlinks map[K]map[K]struct{}
+	// edges is the number of links, counting undirected links once.
+	edges int
+}
+
+// makeAdjacency returns an empty adjacency of mode.
+func makeAdjacency[K comparable, E any](mode Mode) (out adjacency[K, E]) {
+	out.mode = mode
+	out.links = make(map[K]map[K]E)
+	if mode == Directed {
+		out.inlinks = make(map[K]map[K]struct{})
+	}
+	return
+}
+
+// addNode adds key if it does not exist and returns if it was added.
+func (self *adjacency[K, E]) addNode(key K) (added bool) {
+	if _, exists := self.links[key]; exists {
+		return false
+	}
+	self.links[key] = make(map[K]E)
+	if self.mode == Directed {
+		self.inlinks[key] = make(map[K]struct{})
+	}
+	return true
+}
+
+// link links a to b with value v, replacing the value of an existing link,
+// and returns if a and b were already linked.
+func (self *adjacency[K, E]) link(a, b K, v E) (wasLinked bool) {
+	wasLinked = self.linked(a, b)
+	self.addNode(a)
+	self.addNode(b)
+	self.links[a][b] = v
+	if self.mode == Undirected {
+		self.links[b][a] = v
+	} else {
+		self.inlinks[b][a] = struct{}{}
+	}
+	if !wasLinked {
+		self.edges++
+	}
+	return
+}
+
+// unlink unlinks a from b and returns if they were linked.
+func (self *adjacency[K, E]) unlink(a, b K) (wasLinked bool) {
+	if wasLinked = self.linked(a, b); !wasLinked {
+		return
+	}
+	delete(self.links[a], b)
+	if self.mode == Undirected {
+		delete(self.links[b], a)
+	} else {
+		delete(self.inlinks[b], a)
+	}
+	self.edges--
+	return
+}
+
+// linked returns if a links to b.
+func (self *adjacency[K, E]) linked(a, b K) (linked bool) {
+	_, linked = self.links[a][b]
+	return
+}
+
+// edge returns the value of the link from a to b and if it exists.
+func (self *adjacency[K, E]) edge(a, b K) (v E, exists bool) {
+	v, exists = self.links[a][b]
+	return
+}
+
+// removeNode removes key and all links from and to it and returns if it
+// existed.
+func (self *adjacency[K, E]) removeNode(key K) (existed bool) {
+	var out map[K]E
+	if out, existed = self.links[key]; !existed {
+		return
+	}
+	if self.mode == Undirected {
+		self.edges -= len(out)
+		for k := range out {
+			delete(self.links[k], key)
+		}
+	} else {
+		var in = self.inlinks[key]
+		self.edges -= len(out) + len(in)
+		if _, loop := out[key]; loop {
+			self.edges++
+		}
+		for k := range out {
+			delete(self.inlinks[k], key)
+		}
+		for k := range in {
+			delete(self.links[k], key)
+		}
+		delete(self.inlinks, key)
+	}
+	delete(self.links, key)
+	return
+}
+
+// hasNode returns if key exists.
+func (self *adjacency[K, E]) hasNode(key K) (exists bool) {
+	_, exists = self.links[key]
+	return
+}
+
+// outDegree returns the number of links from key.
+func (self *adjacency[K, E]) outDegree(key K) int { return len(self.links[key]) }
+
+// inDegree returns the number of links to key.
+func (self *adjacency[K, E]) inDegree(key K) int {
+	if self.mode == Undirected {
+		return len(self.links[key])
+	}
+	return len(self.inlinks[key])
+}
+
+// enumNodes calls f for each node until f returns false.
+func (self *adjacency[K, E]) enumNodes(f func(key K) bool) {
+	for key := range self.links {
+		if !f(key) {
+			break
+		}
+	}
+}
+
+// nodes returns all nodes.
+func (self *adjacency[K, E]) nodes() (out []K) {
+	out = make([]K, 0, len(self.links))
+	for key := range self.links {
+		out = append(out, key)
+	}
+	return
+}
+
+// enumLinks calls f for each link from key until f returns false.
+func (self *adjacency[K, E]) enumLinks(key K, f func(key K, v E) bool) {
+	for k, v := range self.links[key] {
+		if !f(k, v) {
+			break
+		}
+	}
+}
+
+// enumInLinks calls f for each key linking to key until f returns false.
+func (self *adjacency[K, E]) enumInLinks(key K, f func(key K) bool) {
+	if self.mode == Undirected {
+		for k := range self.links[key] {
+			if !f(k) {
+				break
+			}
+		}
+		return
+	}
+	for k := range self.inlinks[key] {
+		if !f(k) {
+			break
+		}
+	}
+}
+
+// enumEdges calls f for each link until f returns false. A link of an
+// undirected graph is enumerated once.
+func (self *adjacency[K, E]) enumEdges(f func(a, b K, v E) bool) {
+	var done map[K]struct{}
+	if self.mode == Undirected {
+		done = make(map[K]struct{}, len(self.links))
+	}
+	for a, links := range self.links {
+		for b, v := range links {
+			if _, skip := done[b]; skip {
+				continue
+			}
+			if !f(a, b, v) {
+				return
+			}
+		}
+		if done != nil {
+			done[a] = struct{}{}
+		}
+	}
+}
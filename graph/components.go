@@ -0,0 +1,279 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package graph
+
+import "slices"
+
+// UnionFind is a disjoint set forest of keys K. It tracks a partition of
+// keys into sets that are merged with [UnionFind.Union], using path
+// compression and union by size.
+//
+// The zero value of the type is not usable, use [NewUnionFind] to create a
+// new UnionFind.
+//
+// Example:
+//
+//	uf := NewUnionFind[string]()
+//	uf.Union("a", "b")
+//	uf.Union("c", "d")
+//	same := uf.Connected("a", "b") // same will be true
+//	same = uf.Connected("a", "c")  // same will be false
+type UnionFind[K comparable] struct {
+	parent map[K]K
+	size   map[K]int
+	sets   int
+}
+
+// NewUnionFind returns a new empty [UnionFind].
+func NewUnionFind[K comparable]() *UnionFind[K] {
+	return &UnionFind[K]{
+		parent: make(map[K]K),
+		size:   make(map[K]int),
+	}
+}
+
+// Len returns the number of keys.
+func (self *UnionFind[K]) Len() int { return len(self.parent) }
+
+// Sets returns the number of disjoint sets.
+func (self *UnionFind[K]) Sets() int { return self.sets }
+
+// Add adds key as a set of its own if it does not exist and returns if it
+// was added.
+func (self *UnionFind[K]) Add(key K) (added bool) {
+	if _, exists := self.parent[key]; exists {
+		return false
+	}
+	self.parent[key] = key
+	self.size[key] = 1
+	self.sets++
+	return true
+}
+
+// Find returns the representative key of the set of key, adding key as a
+// set of its own if it does not exist. Keys of the same set have the same
+// representative.
+//
+// Example:
+//
+//	uf := NewUnionFind[int]()
+//	uf.Union(1, 2)
+//	same := uf.Find(1) == uf.Find(2) // same will be true
+func (self *UnionFind[K]) Find(key K) (root K) {
+	self.Add(key)
+	for root = key; self.parent[root] != root; {
+		root = self.parent[root]
+	}
+	for key != root {
+		key, self.parent[key] = self.parent[key], root
+	}
+	return
+}
+
+// Union merges the sets of a and b, adding keys that do not exist, and
+// returns if they were in different sets.
+//
+// Example:
+//
+//	uf := NewUnionFind[int]()
+//	merged := uf.Union(1, 2) // merged will be true
+//	merged = uf.Union(2, 1)  // merged will be false
+func (self *UnionFind[K]) Union(a, b K) (merged bool) {
+	var ra, rb = self.Find(a), self.Find(b)
+	if ra == rb {
+		return false
+	}
+	if self.size[ra] < self.size[rb] {
+		ra, rb = rb, ra
+	}
+	self.parent[rb] = ra
+	self.size[ra] += self.size[rb]
+	delete(self.size, rb)
+	self.sets--
+	return true
+}
+
+// Connected returns if a and b exist and are in the same set.
+func (self *UnionFind[K]) Connected(a, b K) bool {
+	if _, exists := self.parent[a]; !exists {
+		return false
+	}
+	if _, exists := self.parent[b]; !exists {
+		return false
+	}
+	return self.Find(a) == self.Find(b)
+}
+
+// SetSize returns the number of keys in the set of key or 0 if key does not
+// exist.
+func (self *UnionFind[K]) SetSize(key K) int {
+	if _, exists := self.parent[key]; !exists {
+		return 0
+	}
+	return self.size[self.Find(key)]
+}
+
+// Groups returns keys grouped by set. The order of groups and of keys
+// within a group is not defined.
+func (self *UnionFind[K]) Groups() (out [][]K) {
+	var index = make(map[K]int, self.sets)
+	out = make([][]K, 0, self.sets)
+	for key := range self.parent {
+		var root = self.Find(key)
+		var i, ok = index[root]
+		if !ok {
+			i = len(out)
+			index[root] = i
+			out = append(out, make([]K, 0, self.size[root]))
+		}
+		out[i] = append(out[i], key)
+	}
+	return
+}
+
+// WeakComponents returns the weakly connected components of g, the groups
+// of nodes connected by links regardless of their direction. The order of
+// components and of keys within a component is not defined.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "b")
+//	g.Link("c", "b")
+//	g.AddNode("d")
+//	components := WeakComponents[string](g) // components will be [[a b c] [d]]
+func WeakComponents[K comparable](g Topology[K]) [][]K {
+	var uf = NewUnionFind[K]()
+	g.EnumNodes(func(key K) bool {
+		uf.Add(key)
+		return true
+	})
+	for _, key := range collectNodes(g) {
+		g.EnumLinks(key, func(k K) bool {
+			uf.Union(key, k)
+			return true
+		})
+	}
+	return uf.Groups()
+}
+
+// StronglyConnected returns the strongly connected components of g found by
+// Tarjan's algorithm. Keys of a component are all reachable from each
+// other. Components are returned in reverse topological order, a component
+// is only linked to from components that follow it. The order of keys
+// within a component is not defined.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "b")
+//	g.Link("b", "a")
+//	g.Link("b", "c")
+//	components := StronglyConnected[string](g) // components will be [[c] [a b]]
+func StronglyConnected[K comparable](g Topology[K]) (out [][]K) {
+	// frame is a key whose links are being followed.
+	type frame struct {
+		key   K
+		links []K
+		next  int
+	}
+	var (
+		index   = make(map[K]int)
+		low     = make(map[K]int)
+		onStack = make(map[K]bool)
+		stack   []K
+		frames  []*frame
+	)
+	var visit = func(key K) {
+		index[key], low[key] = len(index), len(index)
+		stack = append(stack, key)
+		onStack[key] = true
+		frames = append(frames, &frame{key: key, links: collectLinks(g, key, nil)})
+	}
+	for _, node := range collectNodes(g) {
+		if _, ok := index[node]; ok {
+			continue
+		}
+		visit(node)
+		for len(frames) > 0 {
+			var top = frames[len(frames)-1]
+			if top.next < len(top.links) {
+				var key = top.links[top.next]
+				top.next++
+				if _, ok := index[key]; !ok {
+					visit(key)
+				} else if onStack[key] {
+					low[top.key] = min(low[top.key], index[key])
+				}
+				continue
+			}
+			frames = frames[:len(frames)-1]
+			if low[top.key] == index[top.key] {
+				var i = len(stack) - 1
+				for stack[i] != top.key {
+					i--
+				}
+				var component = slices.Clone(stack[i:])
+				for _, key := range component {
+					onStack[key] = false
+				}
+				stack = stack[:i]
+				out = append(out, component)
+			}
+			if len(frames) > 0 {
+				var parent = frames[len(frames)-1].key
+				low[parent] = min(low[parent], low[top.key])
+			}
+		}
+	}
+	return
+}
+
+// Condensation returns the condensation of g, the directed acyclic graph of
+// its strongly connected components.
+//
+// Arguments:
+//
+//	g: The graph to condense.
+//
+// Returns:
+//
+//	components: Strongly connected components of g in topological order.
+//	index:      Index of the component of each node of g.
+//	dag:        A directed graph of component indexes, component i links to
+//	            component j if a key of i links to a key of j. Components
+//	            only link to components of a greater index.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "b")
+//	g.Link("b", "a")
+//	g.Link("b", "c")
+//	components, index, dag := Condensation[string](g)
+//	// components will be [[a b] [c]], index["c"] will be 1, dag.Linked(0, 1) will be true
+func Condensation[K comparable](g Topology[K]) (components [][]K, index map[K]int, dag *Graph[int]) {
+	components = StronglyConnected(g)
+	slices.Reverse(components)
+	index = make(map[K]int)
+	dag = NewGraph[int]()
+	for i, component := range components {
+		dag.AddNode(i)
+		for _, key := range component {
+			index[key] = i
+		}
+	}
+	for i, component := range components {
+		for _, key := range component {
+			g.EnumLinks(key, func(k K) bool {
+				if j := index[k]; j != i {
+					dag.Link(i, j)
+				}
+				return true
+			})
+		}
+	}
+	return
+}
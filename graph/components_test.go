@@ -0,0 +1,139 @@
+package graph
+
+import (
+	"slices"
+	"testing"
+)
+
+// normalize sorts keys of each group and the groups for comparison.
+func normalize(groups [][]string) [][]string {
+	for _, g := range groups {
+		slices.Sort(g)
+	}
+	slices.SortFunc(groups, func(a, b []string) int { return slices.Compare(a, b) })
+	return groups
+}
+
+func TestUnionFind(t *testing.T) {
+	uf := NewUnionFind[int]()
+	for i := range 10 {
+		uf.Add(i)
+	}
+	if uf.Add(0) || uf.Len() != 10 || uf.Sets() != 10 {
+		t.Fatal("Add should add 10 sets once")
+	}
+	for i := 0; i < 10; i += 2 {
+		uf.Union(i, (i+2)%10)
+	}
+	if uf.Union(0, 8) {
+		t.Error("Union of connected keys should return false")
+	}
+	if !uf.Union(1, 3) || uf.Sets() != 5 {
+		t.Errorf("Sets() should be 5, got %d", uf.Sets())
+	}
+	if !uf.Connected(0, 6) || uf.Connected(0, 1) || !uf.Connected(3, 1) {
+		t.Error("unexpected connectivity")
+	}
+	if uf.Connected(0, 42) || uf.SetSize(42) != 0 {
+		t.Error("unknown keys should not be connected")
+	}
+	if uf.SetSize(4) != 5 || uf.SetSize(1) != 2 || uf.SetSize(9) != 1 {
+		t.Errorf("unexpected set sizes %d %d %d", uf.SetSize(4), uf.SetSize(1), uf.SetSize(9))
+	}
+	if uf.Find(2) != uf.Find(8) {
+		t.Error("Find should return the same root for a set")
+	}
+	groups := uf.Groups()
+	if len(groups) != 5 {
+		t.Errorf("Groups() should return 5 groups, got %d", len(groups))
+	}
+	uf.Find(42)
+	if uf.Len() != 11 || uf.Sets() != 6 {
+		t.Error("Find should add unknown keys")
+	}
+}
+
+func TestWeakComponents(t *testing.T) {
+	g := NewGraph[string]()
+	g.Link("a", "b")
+	g.Link("c", "b")
+	g.Link("d", "e")
+	g.AddNode("f")
+	got := normalize(WeakComponents[string](g))
+	want := [][]string{{"a", "b", "c"}, {"d", "e"}, {"f"}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("WeakComponents should be %v, got %v", want, got)
+	}
+}
+
+func sccGraph() *Graph[string] {
+	g := NewGraph[string]()
+	g.Link("a", "b")
+	g.Link("b", "c")
+	g.Link("c", "a")
+	g.Link("c", "d")
+	g.Link("d", "e")
+	g.Link("e", "d")
+	g.Link("e", "f")
+	g.Link("g", "g")
+	g.Link("g", "a")
+	return g
+}
+
+func TestStronglyConnected(t *testing.T) {
+	g := sccGraph()
+	got := StronglyConnected[string](g)
+	// Reverse topological order: no component links to a later one.
+	pos := map[string]int{}
+	for i, c := range got {
+		for _, k := range c {
+			pos[k] = i
+		}
+	}
+	for a, b := range g.Edges() {
+		if pos[a] < pos[b] {
+			t.Errorf("component of %s should follow component of %s", a, b)
+		}
+	}
+	want := [][]string{{"a", "b", "c"}, {"d", "e"}, {"f"}, {"g"}}
+	if got = normalize(got); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("StronglyConnected should be %v, got %v", want, got)
+	}
+
+	// A long chain must not overflow the stack.
+	chain := NewGraph[int]()
+	for i := range 100000 {
+		chain.Link(i, i+1)
+	}
+	chain.Link(100000, 0)
+	if c := StronglyConnected[int](chain); len(c) != 1 || len(c[0]) != 100001 {
+		t.Errorf("chain cycle should be a single component, got %d", len(c))
+	}
+}
+
+func TestCondensation(t *testing.T) {
+	g := sccGraph()
+	components, index, dag := Condensation[string](g)
+	if len(components) != 4 || dag.NodeCount() != 4 {
+		t.Fatalf("should condense to 4 components, got %v", components)
+	}
+	for key, i := range index {
+		if !slices.Contains(components[i], key) {
+			t.Errorf("index of %s should point to its component", key)
+		}
+	}
+	if !dag.Linked(index["g"], index["a"]) || !dag.Linked(index["c"], index["d"]) || !dag.Linked(index["e"], index["f"]) {
+		t.Error("dag should link components")
+	}
+	if dag.EdgeCount() != 3 {
+		t.Errorf("dag should have 3 links, got %d", dag.EdgeCount())
+	}
+	for a, b := range dag.Edges() {
+		if a >= b {
+			t.Errorf("component %d should not link to earlier component %d", a, b)
+		}
+	}
+	if _, err := TopoSort[int](dag); err != nil {
+		t.Errorf("dag should be acyclic, got %v", err)
+	}
+}
@@ -0,0 +1,666 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidDOT is returned by [ReadDOT] when the input is not valid DOT or
+// uses features outside of the supported subset.
+var ErrInvalidDOT = errors.New("invalid dot")
+
+// DOTOptions configure [WriteDOT]. All fields are optional.
+//
+// Node IDs and the graph name are written literally and read back by
+// [ReadDOT] unchanged. Labels and other attribute values are written as
+// Graphviz escString values, so escapes such as \n, \l and \r can be used
+// for multi-line labels. Only unescaped quotes and a trailing backslash of a
+// value are escaped.
+type DOTOptions[K comparable] struct {
+	// Name is the name of the graph.
+	Name string
+	// ID returns the DOT node ID of a key. If nil keys are formatted with
+	// fmt.Sprint. IDs must be unique.
+	ID func(key K) string
+	// NodeLabel returns the label of a node. If nil or if it returns an
+	// empty string the node has no label and is shown by its ID.
+	NodeLabel func(key K) string
+	// NodeAttrs returns additional attributes of a node.
+	NodeAttrs func(key K) map[string]string
+	// EdgeLabel returns the label of the link from a to b. If nil or if it
+	// returns an empty string the link has no label.
+	EdgeLabel func(a, b K) string
+	// EdgeAttrs returns additional attributes of the link from a to b.
+	EdgeAttrs func(a, b K) map[string]string
+	// GraphAttrs are attributes of the graph, e.g. "rankdir": "LR".
+	GraphAttrs map[string]string
+}
+
+// WriteDOT writes g to w in Graphviz DOT format.
+//
+// If g has a Directed() bool method, such as [Graph] and [ValueGraph], and
+// it returns false, an undirected graph is written with each link once.
+// Otherwise a directed graph is written. Nodes are written ordered by ID and
+// attributes ordered by name so that output is stable. All IDs are quoted.
+//
+// Arguments:
+//
+//	w:    The writer to write to.
+//	g:    The graph to write.
+//	opts: Output options, may be nil.
+//
+// Returns:
+//
+//	err: An error writing to w.
+//
+// Example:
+//
+//	g := NewValueGraph[string, int, struct{}]()
+//	g.LinkWith("api", "db", 5)
+//	err := WriteDOT[string](os.Stdout, g, &DOTOptions[string]{
+//		EdgeLabel: func(a, b string) string {
+//			v, _ := g.Edge(a, b)
+//			return fmt.Sprintf("%dms", v)
+//		},
+//	})
+//	// Output:
+//	// digraph {
+//	// 	"api";
+//	// 	"db";
+//	// 	"api" -> "db" [label="5ms"];
+//	// }
+func WriteDOT[K comparable](w io.Writer, g Topology[K], opts *DOTOptions[K]) (err error) {
+	var o DOTOptions[K]
+	if opts != nil {
+		o = *opts
+	}
+	if o.ID == nil {
+		o.ID = func(key K) string { return fmt.Sprint(key) }
+	}
+	var (
+		directed = true
+		kind     = "digraph"
+		op       = "->"
+	)
+	if d, ok := g.(interface{ Directed() bool }); ok && !d.Directed() {
+		directed, kind, op = false, "graph", "--"
+	}
+
+	var (
+		nodes = collectNodes(g)
+		ids   = make(map[K]string, len(nodes))
+	)
+	for _, key := range nodes {
+		ids[key] = o.ID(key)
+	}
+	slices.SortFunc(nodes, func(a, b K) int { return strings.Compare(ids[a], ids[b]) })
+
+	var bw = bufio.NewWriter(w)
+	bw.WriteString(kind)
+	if o.Name != "" {
+		bw.WriteString(" " + dotQuote(o.Name))
+	}
+	bw.WriteString(" {\n")
+	for _, name := range slices.Sorted(maps.Keys(o.GraphAttrs)) {
+		fmt.Fprintf(bw, "\t%s=%s;\n", dotQuote(name), dotValue(o.GraphAttrs[name]))
+	}
+	for _, key := range nodes {
+		var attrs map[string]string
+		if o.NodeAttrs != nil {
+			attrs = o.NodeAttrs(key)
+		}
+		if o.NodeLabel != nil {
+			attrs = withLabel(attrs, o.NodeLabel(key))
+		}
+		fmt.Fprintf(bw, "\t%s%s;\n", dotQuote(ids[key]), dotAttrs(attrs))
+	}
+	var (
+		done  = make(map[K]struct{})
+		links []K
+	)
+	for _, a := range nodes {
+		links = collectLinks(g, a, links[:0])
+		slices.SortFunc(links, func(x, y K) int { return strings.Compare(ids[x], ids[y]) })
+		for _, b := range links {
+			if _, skip := done[b]; skip {
+				continue
+			}
+			var attrs map[string]string
+			if o.EdgeAttrs != nil {
+				attrs = o.EdgeAttrs(a, b)
+			}
+			if o.EdgeLabel != nil {
+				attrs = withLabel(attrs, o.EdgeLabel(a, b))
+			}
+			fmt.Fprintf(bw, "\t%s %s %s%s;\n", dotQuote(ids[a]), op, dotQuote(ids[b]), dotAttrs(attrs))
+		}
+		if !directed {
+			done[a] = struct{}{}
+		}
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// ReadDOT reads a graph in Graphviz DOT format from r. A "digraph" is read
+// into a directed [Graph], a "graph" into an undirected one.
+//
+// It supports a practical subset of DOT meant for writing fixtures by hand:
+// "//", "#" and "/* */" comments, the "strict" keyword, graph names, node
+// statements, edge statements with chains such as "a -> b -> c", subgraphs
+// and "{a b}" groups as edge operands, identifiers, numerals, quoted and
+// HTML strings, and node ports. Attributes are parsed and ignored.
+//
+// Arguments:
+//
+//	r: The reader to read from.
+//
+// Returns:
+//
+//	g:   The graph read.
+//	err: An error wrapping [ErrInvalidDOT] or an error reading r.
+//
+// Example:
+//
+//	g, err := ReadDOT(strings.NewReader(`digraph { a -> {b c}; c -> d [label="x"] }`))
+//	linked := g.Linked("a", "c") // linked will be true
+func ReadDOT(r io.Reader) (g *Graph[string], err error) {
+	var data []byte
+	if data, err = io.ReadAll(r); err != nil {
+		return nil, err
+	}
+	var p = &dotParser{lex: dotLexer{src: []rune(string(data)), line: 1}}
+	if err = p.parse(); err != nil {
+		return nil, err
+	}
+	return p.graph, nil
+}
+
+// dotQuote returns s as a quoted DOT ID with quotes and backslashes escaped
+// so that [ReadDOT] reads back the same string.
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+// dotEscaper escapes quotes and backslashes of quoted DOT IDs.
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// dotValue returns s as a quoted DOT attribute value. Escape sequences in s
+// are kept as written so that Graphviz interprets them, unescaped quotes are
+// escaped and a trailing backslash is doubled so the string stays
+// terminated.
+func dotValue(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s):
+			sb.WriteByte(c)
+			i++
+			sb.WriteByte(s[i])
+		case c == '\\':
+			sb.WriteString(`\\`)
+		case c == '"':
+			sb.WriteString(`\"`)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// dotAttrs returns attrs as a DOT attribute list ordered by name or an
+// empty string if attrs is empty.
+func dotAttrs(attrs map[string]string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(" [")
+	for i, name := range slices.Sorted(maps.Keys(attrs)) {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(name + "=" + dotValue(attrs[name]))
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+// withLabel returns attrs with a label attribute if label is not empty. It
+// does not modify attrs.
+func withLabel(attrs map[string]string, label string) map[string]string {
+	if label == "" {
+		return attrs
+	}
+	var out = maps.Clone(attrs)
+	if out == nil {
+		out = make(map[string]string, 1)
+	}
+	out["label"] = label
+	return out
+}
+
+// dotTokenKind is the kind of a dotToken.
+type dotTokenKind int
+
+const (
+	dotEOF dotTokenKind = iota
+	// dotID is an identifier, numeral, quoted or HTML string.
+	dotID
+	// dotKeyword is an unquoted keyword, lowercased.
+	dotKeyword
+	// dotEdgeOp is "->" or "--".
+	dotEdgeOp
+	// dotPunct is one of "{}[];,=:".
+	dotPunct
+)
+
+// dotToken is a token of DOT input.
+type dotToken struct {
+	kind dotTokenKind
+	text string
+	line int
+}
+
+// String returns the token for error messages.
+func (self dotToken) String() string {
+	if self.kind == dotEOF {
+		return "end of input"
+	}
+	return fmt.Sprintf("%q", self.text)
+}
+
+// dotLexer splits DOT input into tokens.
+type dotLexer struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+// next returns the next token.
+func (self *dotLexer) next() (tok dotToken, err error) {
+	if err = self.skip(); err != nil {
+		return
+	}
+	tok.line = self.line
+	if self.pos >= len(self.src) {
+		return
+	}
+	var c = self.src[self.pos]
+	switch {
+	case strings.ContainsRune("{}[];,=:", c):
+		self.pos++
+		return dotToken{kind: dotPunct, text: string(c), line: tok.line}, nil
+	case c == '-' && self.peek(1) == '>' || c == '-' && self.peek(1) == '-':
+		self.pos += 2
+		return dotToken{kind: dotEdgeOp, text: string(self.src[self.pos-2 : self.pos]), line: tok.line}, nil
+	case c == '"':
+		return self.quoted()
+	case c == '<':
+		return self.html()
+	case unicode.IsDigit(c) || c == '.' || c == '-' && (unicode.IsDigit(self.peek(1)) || self.peek(1) == '.'):
+		var start = self.pos
+		for self.pos++; self.pos < len(self.src); self.pos++ {
+			if c = self.src[self.pos]; c != '.' && !unicode.IsDigit(c) {
+				break
+			}
+		}
+		return dotToken{kind: dotID, text: string(self.src[start:self.pos]), line: tok.line}, nil
+	case c == '_' || unicode.IsLetter(c):
+		var start = self.pos
+		for self.pos++; self.pos < len(self.src); self.pos++ {
+			if c = self.src[self.pos]; c != '_' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+				break
+			}
+		}
+		tok = dotToken{kind: dotID, text: string(self.src[start:self.pos]), line: tok.line}
+		switch strings.ToLower(tok.text) {
+		case "strict", "graph", "digraph", "node", "edge", "subgraph":
+			tok.kind, tok.text = dotKeyword, strings.ToLower(tok.text)
+		}
+		return
+	}
+	return tok, fmt.Errorf("%w: line %d: unexpected character %q", ErrInvalidDOT, self.line, c)
+}
+
+// peek returns the rune at offset n from the current position or 0.
+func (self *dotLexer) peek(n int) rune {
+	if self.pos+n < len(self.src) {
+		return self.src[self.pos+n]
+	}
+	return 0
+}
+
+// skip skips whitespace and comments.
+func (self *dotLexer) skip() error {
+	for self.pos < len(self.src) {
+		var c = self.src[self.pos]
+		switch {
+		case c == '\n':
+			self.line++
+			self.pos++
+		case unicode.IsSpace(c):
+			self.pos++
+		case c == '#' || c == '/' && self.peek(1) == '/':
+			for self.pos < len(self.src) && self.src[self.pos] != '\n' {
+				self.pos++
+			}
+		case c == '/' && self.peek(1) == '*':
+			var line = self.line
+			for self.pos += 2; ; self.pos++ {
+				if self.pos >= len(self.src) {
+					return fmt.Errorf("%w: line %d: unterminated comment", ErrInvalidDOT, line)
+				}
+				if self.src[self.pos] == '\n' {
+					self.line++
+				}
+				if self.src[self.pos] == '*' && self.peek(1) == '/' {
+					self.pos += 2
+					break
+				}
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// quoted reads a quoted string. The \" and \\ escapes are interpreted,
+// other escapes are kept as written.
+func (self *dotLexer) quoted() (tok dotToken, err error) {
+	tok = dotToken{kind: dotID, line: self.line}
+	var sb strings.Builder
+	for self.pos++; self.pos < len(self.src); self.pos++ {
+		var c = self.src[self.pos]
+		switch {
+		case c == '"':
+			self.pos++
+			tok.text = sb.String()
+			return
+		case c == '\\' && (self.peek(1) == '"' || self.peek(1) == '\\'):
+			self.pos++
+			c = self.src[self.pos]
+		case c == '\\' && self.peek(1) == '\n':
+			self.pos++
+			self.line++
+			continue
+		case c == '\n':
+			self.line++
+		}
+		sb.WriteRune(c)
+	}
+	return tok, fmt.Errorf("%w: line %d: unterminated string", ErrInvalidDOT, tok.line)
+}
+
+// html reads an HTML string enclosed in balanced angle brackets.
+func (self *dotLexer) html() (tok dotToken, err error) {
+	tok = dotToken{kind: dotID, line: self.line}
+	var start, depth = self.pos + 1, 0
+	for ; self.pos < len(self.src); self.pos++ {
+		switch self.src[self.pos] {
+		case '<':
+			depth++
+		case '>':
+			if depth--; depth == 0 {
+				tok.text = string(self.src[start:self.pos])
+				self.pos++
+				return
+			}
+		case '\n':
+			self.line++
+		}
+	}
+	return tok, fmt.Errorf("%w: line %d: unterminated html string", ErrInvalidDOT, tok.line)
+}
+
+// dotParser parses DOT tokens into a graph.
+type dotParser struct {
+	lex   dotLexer
+	tok   dotToken
+	graph *Graph[string]
+	op    string
+}
+
+// advance reads the next token.
+func (self *dotParser) advance() (err error) {
+	self.tok, err = self.lex.next()
+	return
+}
+
+// is returns if the current token is of kind with text.
+func (self *dotParser) is(kind dotTokenKind, text string) bool {
+	return self.tok.kind == kind && self.tok.text == text
+}
+
+// expect consumes the current token if it is of kind with text or returns
+// an error.
+func (self *dotParser) expect(kind dotTokenKind, text string) error {
+	if !self.is(kind, text) {
+		return self.errorf("expected %q, got %s", text, self.tok)
+	}
+	return self.advance()
+}
+
+// errorf returns an error wrapping ErrInvalidDOT at the current token.
+func (self *dotParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: line %d: %s", ErrInvalidDOT, self.tok.line, fmt.Sprintf(format, args...))
+}
+
+// parse parses a whole graph.
+func (self *dotParser) parse() (err error) {
+	if err = self.advance(); err != nil {
+		return
+	}
+	if self.is(dotKeyword, "strict") {
+		if err = self.advance(); err != nil {
+			return
+		}
+	}
+	switch {
+	case self.is(dotKeyword, "digraph"):
+		self.graph, self.op = NewGraph[string](), "->"
+	case self.is(dotKeyword, "graph"):
+		self.graph, self.op = NewUndirectedGraph[string](), "--"
+	default:
+		return self.errorf("expected graph or digraph, got %s", self.tok)
+	}
+	if err = self.advance(); err != nil {
+		return
+	}
+	if self.tok.kind == dotID {
+		if err = self.advance(); err != nil {
+			return
+		}
+	}
+	if err = self.expect(dotPunct, "{"); err != nil {
+		return
+	}
+	if _, err = self.stmtList(); err != nil {
+		return
+	}
+	if err = self.expect(dotPunct, "}"); err != nil {
+		return
+	}
+	if self.tok.kind != dotEOF {
+		return self.errorf("unexpected %s after graph", self.tok)
+	}
+	return nil
+}
+
+// stmtList parses statements up to a closing brace and returns all nodes
+// they mention.
+func (self *dotParser) stmtList() (nodes []string, err error) {
+	for !self.is(dotPunct, "}") {
+		if self.tok.kind == dotEOF {
+			return nil, self.errorf("expected \"}\", got %s", self.tok)
+		}
+		var mentioned []string
+		if mentioned, err = self.stmt(); err != nil {
+			return
+		}
+		nodes = append(nodes, mentioned...)
+		if self.is(dotPunct, ";") {
+			if err = self.advance(); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// stmt parses a statement and returns the nodes it mentions.
+func (self *dotParser) stmt() (nodes []string, err error) {
+	if self.is(dotKeyword, "graph") || self.is(dotKeyword, "node") || self.is(dotKeyword, "edge") {
+		if err = self.advance(); err != nil {
+			return
+		}
+		return nil, self.attrLists()
+	}
+	if self.tok.kind == dotID {
+		var name = self.tok.text
+		if err = self.advance(); err != nil {
+			return
+		}
+		if self.is(dotPunct, "=") {
+			if err = self.advance(); err != nil {
+				return
+			}
+			return nil, self.id()
+		}
+		if err = self.port(); err != nil {
+			return
+		}
+		self.graph.AddNode(name)
+		nodes = []string{name}
+	} else if nodes, err = self.subgraph(); err != nil {
+		return
+	}
+	var left = nodes
+	for self.tok.kind == dotEdgeOp {
+		if self.tok.text != self.op {
+			return nil, self.errorf("edge operator %q in graph using %q", self.tok.text, self.op)
+		}
+		if err = self.advance(); err != nil {
+			return
+		}
+		var right []string
+		if right, err = self.operand(); err != nil {
+			return
+		}
+		for _, a := range left {
+			for _, b := range right {
+				self.graph.Link(a, b)
+			}
+		}
+		nodes = append(nodes, right...)
+		left = right
+	}
+	return nodes, self.attrLists()
+}
+
+// operand parses a node ID with an optional port or a subgraph and returns
+// its nodes.
+func (self *dotParser) operand() (nodes []string, err error) {
+	if self.tok.kind != dotID {
+		return self.subgraph()
+	}
+	var name = self.tok.text
+	if err = self.advance(); err != nil {
+		return
+	}
+	if err = self.port(); err != nil {
+		return
+	}
+	self.graph.AddNode(name)
+	return []string{name}, nil
+}
+
+// subgraph parses "subgraph [ID] { ... }" or "{ ... }" and returns the nodes
+// it mentions.
+func (self *dotParser) subgraph() (nodes []string, err error) {
+	if self.is(dotKeyword, "subgraph") {
+		if err = self.advance(); err != nil {
+			return
+		}
+		if self.tok.kind == dotID {
+			if err = self.advance(); err != nil {
+				return
+			}
+		}
+	}
+	if !self.is(dotPunct, "{") {
+		return nil, self.errorf("unexpected %s", self.tok)
+	}
+	if err = self.advance(); err != nil {
+		return
+	}
+	if nodes, err = self.stmtList(); err != nil {
+		return
+	}
+	return nodes, self.expect(dotPunct, "}")
+}
+
+// port skips an optional ":port[:compass]" after a node ID.
+func (self *dotParser) port() (err error) {
+	for i := 0; i < 2 && self.is(dotPunct, ":"); i++ {
+		if err = self.advance(); err != nil {
+			return
+		}
+		if err = self.id(); err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+// id consumes an ID or returns an error.
+func (self *dotParser) id() error {
+	if self.tok.kind != dotID {
+		return self.errorf("expected ID, got %s", self.tok)
+	}
+	return self.advance()
+}
+
+// attrLists skips any number of "[name=value, ...]" attribute lists.
+func (self *dotParser) attrLists() (err error) {
+	for self.is(dotPunct, "[") {
+		if err = self.advance(); err != nil {
+			return
+		}
+		for !self.is(dotPunct, "]") {
+			if err = self.id(); err != nil {
+				return
+			}
+			if self.is(dotPunct, "=") {
+				if err = self.advance(); err != nil {
+					return
+				}
+				if err = self.id(); err != nil {
+					return
+				}
+			}
+			if self.is(dotPunct, ",") || self.is(dotPunct, ";") {
+				if err = self.advance(); err != nil {
+					return
+				}
+			}
+		}
+		if err = self.advance(); err != nil {
+			return
+		}
+	}
+	return nil
+}
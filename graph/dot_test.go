@@ -0,0 +1,203 @@
+package graph
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	g := NewValueGraph[string, int, string]()
+	g.LinkWith("api", "db", 5)
+	g.LinkWith("api", "cache", 1)
+	g.SetNode("db", `pg "main"`)
+	g.AddNode("lonely")
+
+	var buf bytes.Buffer
+	err := WriteDOT[string](&buf, g, &DOTOptions[string]{
+		Name: "services",
+		NodeLabel: func(key string) string {
+			label, _ := g.Node(key)
+			return label
+		},
+		NodeAttrs: func(key string) map[string]string {
+			if key == "api" {
+				return map[string]string{"shape": "box"}
+			}
+			return nil
+		},
+		EdgeLabel: func(a, b string) string {
+			v, _ := g.Edge(a, b)
+			return fmt.Sprintf("%dms", v)
+		},
+		GraphAttrs: map[string]string{"rankdir": "LR"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph "services" {
+	"rankdir"="LR";
+	"api" [shape="box"];
+	"cache";
+	"db" [label="pg \"main\""];
+	"lonely";
+	"api" -> "cache" [label="1ms"];
+	"api" -> "db" [label="5ms"];
+}
+`
+	if buf.String() != want {
+		t.Errorf("WriteDOT should write\n%s\ngot\n%s", want, buf.String())
+	}
+
+	u := NewUndirectedGraph[int]()
+	u.Link(1, 2)
+	u.Link(2, 3)
+	u.Link(3, 3)
+	buf.Reset()
+	if err = WriteDOT[int](&buf, u, nil); err != nil {
+		t.Fatal(err)
+	}
+	want = "graph {\n\t\"1\";\n\t\"2\";\n\t\"3\";\n\t\"1\" -- \"2\";\n\t\"2\" -- \"3\";\n\t\"3\" -- \"3\";\n}\n"
+	if buf.String() != want {
+		t.Errorf("WriteDOT should write\n%s\ngot\n%s", want, buf.String())
+	}
+}
+
+func TestWriteDOTEscapes(t *testing.T) {
+	g := NewGraph[string]()
+	g.Link(`a\`, "b")
+	var buf bytes.Buffer
+	err := WriteDOT[string](&buf, g, &DOTOptions[string]{
+		NodeLabel: func(key string) string {
+			if key == "b" {
+				return `tail\`
+			}
+			return `first\nsecond\l"quoted" \"kept\"`
+		},
+		EdgeLabel: func(a, b string) string { return `x\ly\l` },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Label escapes are kept for Graphviz, IDs escape backslashes.
+	want := `digraph {
+	"a\\" [label="first\nsecond\l\"quoted\" \"kept\""];
+	"b" [label="tail\\"];
+	"a\\" -> "b" [label="x\ly\l"];
+}
+`
+	if buf.String() != want {
+		t.Errorf("WriteDOT should write\n%s\ngot\n%s", want, buf.String())
+	}
+	if _, err = ReadDOT(&buf); err != nil {
+		t.Fatalf("ReadDOT should read escaped output, got %v", err)
+	}
+}
+
+func TestReadDOT(t *testing.T) {
+	const src = `/* build fixture */
+strict digraph "build" {
+	// defaults
+	graph [rankdir=LR];
+	node [shape=box, color="red"]
+	edge [style=dashed]
+	label = "Build";
+	# nodes
+	fetch; docs [label=<<b>Docs</b>>]
+	fetch -> build -> test [weight=2];
+	fetch -> {lint vet};
+	{lint vet} -> test
+	subgraph cluster_release {
+		release:n -> "publish site":w:s
+	}
+	test -> release; docs -> "publish site"
+	"multi
+line" -> -1.5
+}`
+	g, err := ReadDOT(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Directed() {
+		t.Error("digraph should be directed")
+	}
+	nodes := g.Nodes()
+	slices.Sort(nodes)
+	want := []string{"-1.5", "build", "docs", "fetch", "lint", "multi\nline", "publish site", "release", "test", "vet"}
+	if !slices.Equal(nodes, want) {
+		t.Errorf("nodes should be %q, got %q", want, nodes)
+	}
+	for _, link := range [][2]string{
+		{"fetch", "build"}, {"build", "test"}, {"fetch", "lint"}, {"fetch", "vet"},
+		{"lint", "test"}, {"vet", "test"}, {"release", "publish site"},
+		{"test", "release"}, {"docs", "publish site"}, {"multi\nline", "-1.5"},
+	} {
+		if !g.Linked(link[0], link[1]) {
+			t.Errorf("%q should link to %q", link[0], link[1])
+		}
+	}
+	if g.EdgeCount() != 10 {
+		t.Errorf("EdgeCount() should be 10, got %d", g.EdgeCount())
+	}
+
+	u, err := ReadDOT(strings.NewReader(`graph { a -- b -- c }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Directed() || !u.Linked("c", "b") || u.EdgeCount() != 2 {
+		t.Error("graph should be undirected with 2 links")
+	}
+}
+
+func TestReadDOTErrors(t *testing.T) {
+	for _, src := range []string{
+		``,
+		`digraph`,
+		`digraph { a -> b`,
+		`digraph { a -- b }`,
+		`graph { a -> b }`,
+		`digraph { a -> }`,
+		`digraph { "a }`,
+		`digraph { /* a }`,
+		`digraph { a [color=] }`,
+		`digraph { a @ b }`,
+		`digraph { } extra`,
+		`tree { }`,
+	} {
+		if _, err := ReadDOT(strings.NewReader(src)); !errors.Is(err, ErrInvalidDOT) {
+			t.Errorf("ReadDOT(%q) should fail with ErrInvalidDOT, got %v", src, err)
+		}
+	}
+}
+
+func TestDOTRoundTrip(t *testing.T) {
+	for _, g := range []*Graph[string]{NewGraph[string](), NewUndirectedGraph[string]()} {
+		g.Link("a", `b "quoted"`)
+		g.Link("multi\nline", "a")
+		g.Link(`b "quoted"`, "c")
+		g.Link("c", "a")
+		g.AddNode("d")
+		g.Link(`a\`, `\"`)
+		g.Link(`\\`, "back\\\nslash")
+		var buf bytes.Buffer
+		if err := WriteDOT[string](&buf, g, nil); err != nil {
+			t.Fatal(err)
+		}
+		out, err := ReadDOT(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.Directed() != g.Directed() || out.NodeCount() != g.NodeCount() || out.EdgeCount() != g.EdgeCount() {
+			t.Fatalf("round trip changed the graph: %d/%d nodes, %d/%d links",
+				out.NodeCount(), g.NodeCount(), out.EdgeCount(), g.EdgeCount())
+		}
+		for a, b := range g.Edges() {
+			if !out.Linked(a, b) {
+				t.Errorf("%q should link to %q after round trip", a, b)
+			}
+		}
+	}
+}
@@ -5,27 +5,74 @@
 // Package graph implements a generic graph data structure.
 package graph
 
-import "sync"
+import (
+	"iter"
+	"sync"
+)
+
+// Mode is the edge semantics of a [Graph].
+type Mode int
+
+const (
+	// Directed graphs link a to b without linking b to a.
+	Directed Mode = iota
+	// Undirected graphs link a and b both ways.
+	Undirected
+)
+
+// String returns the name of the mode.
+func (self Mode) String() string {
+	if self == Undirected {
+		return "undirected"
+	}
+	return "directed"
+}
 
 // Graph is a many-to many map of generic comparable entries.
 // The graph structure stores links between keys of a comparable type K.
+//
+// A graph is either [Directed] or [Undirected] as chosen by the constructor.
+// Links of a directed graph lead from a to b and a directed graph also
+// tracks the reverse links, see [Graph.InLinks]. Links of an undirected
+// graph are symmetric, linking a to b also links b to a.
+//
+// Keys are nodes of the graph and links are its edges. Linking keys adds
+// them as nodes, a node without links can be added with [Graph.AddNode].
+//
+// Graph stores only the set of links, see [ValueGraph] for a graph that
+// stores values on links and nodes.
 type Graph[K comparable] struct {
-	links map[K]map[K]struct{}
+	adj adjacency[K, struct{}]
 }
 
-// NewGraph returns a new [Graph].
+// NewGraph returns a new directed [Graph].
 //
 // Example:
 //
 //	g := NewGraph[int]()
 func NewGraph[K comparable]() *Graph[K] {
-	return &Graph[K]{
-		links: make(map[K]map[K]struct{}),
-	}
+	return &Graph[K]{adj: makeAdjacency[K, struct{}](Directed)}
+}
+
+// NewUndirectedGraph returns a new undirected [Graph].
+//
+// Example:
+//
+//	g := NewUndirectedGraph[int]()
+//	g.Link(1, 2)
+//	linked := g.Linked(2, 1) // linked will be true
+func NewUndirectedGraph[K comparable]() *Graph[K] {
+	return &Graph[K]{adj: makeAdjacency[K, struct{}](Undirected)}
 }
 
+// Mode returns the mode of the graph.
+func (self *Graph[K]) Mode() Mode { return self.adj.mode }
+
+// Directed returns true if the graph is directed.
+func (self *Graph[K]) Directed() bool { return self.adj.mode == Directed }
+
 // Link links a and b if not already linked and returns if a and b were already
-// linked prior to this call.
+// linked prior to this call. In an undirected graph b is also linked to a.
 //
 // Arguments:
 //
@@ -42,16 +89,12 @@ func NewGraph[K comparable]() *Graph[K] {
 //	wasLinked := g.Link("a", "b") // wasLinked will be false
 //	wasLinked = g.Link("a", "b")    // wasLinked will be true
 func (self *Graph[K]) Link(a, b K) (wasLinked bool) {
-	wasLinked = self.Linked(a, b)
-	if _, exists := self.links[a]; !exists {
-		self.links[a] = make(map[K]struct{})
-	}
-	self.links[a][b] = struct{}{}
-	return
+	return self.adj.link(a, b, struct{}{})
 }
 
 // Unlink breaks the link between a and b if it exists and returns if the link
-// existed prior to this call.
+// existed prior to this call. In an undirected graph b is also unlinked from
+// a.
 //
 // Arguments:
 //
@@ -69,15 +112,11 @@ func (self *Graph[K]) Link(a, b K) (wasLinked bool) {
 //	wasLinked := g.Unlink("a", "b") // wasLinked will be true
 //	wasLinked = g.Unlink("a", "b")    // wasLinked will be false
 func (self *Graph[K]) Unlink(a, b K) (wasLinked bool) {
-	wasLinked = self.Linked(a, b)
-	if m, exists := self.links[a]; exists {
-		delete(m, b)
-		self.links[a] = m
-	}
-	return
+	return self.adj.unlink(a, b)
 }
 
-// Linked returns if a and b are linked.
+// Linked returns if a and b are linked. In a directed graph the link must
+// lead from a to b.
 //
 // Arguments:
 //
@@ -95,10 +134,7 @@ func (self *Graph[K]) Unlink(a, b K) (wasLinked bool) {
 //	linked := g.Linked("a", "b") // linked will be true
 //	linked = g.Linked("b", "a")    // linked will be false
 func (self *Graph[K]) Linked(a, b K) (linked bool) {
-	if links, exists := self.links[a]; exists {
-		_, linked = links[b]
-	}
-	return
+	return self.adj.linked(a, b)
 }
 
 // Links returns names of all keys a key links to.
@@ -118,9 +154,7 @@ func (self *Graph[K]) Linked(a, b K) (linked bool) {
 //	g.Link("a", "c")
 //	links := g.Links("a") // links will be []string{"b", "c"} or []string{"c", "b"} (order not guaranteed)
 func (self *Graph[K]) Links(key K) (out []K) {
-	if out = make([]K, 0, len(self.links[key])); cap(out) == 0 {
-		return
-	}
+	out = make([]K, 0, self.adj.outDegree(key))
 	self.EnumLinks(key, func(k K) bool {
 		out = append(out, k)
 		return true
@@ -147,12 +181,167 @@ func (self *Graph[K]) Links(key K) (out []K) {
 //		return true
 //	})
 func (self *Graph[K]) EnumLinks(key K, f func(key K) bool) {
-	if m, exists := self.links[key]; exists {
-		for key := range m {
-			if !f(key) {
-				break
-			}
-		}
+	self.adj.enumLinks(key, func(k K, _ struct{}) bool { return f(k) })
+}
+
+// InLinks returns all keys that link to a key. In an undirected graph these
+// are the same as [Graph.Links].
+//
+// Arguments:
+//
+//	key: The key to get the links to.
+//
+// Returns:
+//
+//	out: A slice containing all keys that link to 'key'.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "c")
+//	g.Link("b", "c")
+//	links := g.InLinks("c") // links will be []string{"a", "b"} (order not guaranteed)
+func (self *Graph[K]) InLinks(key K) (out []K) {
+	out = make([]K, 0, self.adj.inDegree(key))
+	self.EnumInLinks(key, func(k K) bool {
+		out = append(out, k)
+		return true
+	})
+	return
+}
+
+// EnumInLinks calls f for each key that links to a key. It keeps calling f
+// until all links have been enumerated or f returns false. In an undirected
+// graph it is the same as [Graph.EnumLinks].
+//
+// Arguments:
+//
+//	key: The key to enumerate links to.
+//	f:   The function to call for each key linking to 'key'.
+//	     If the function returns false, the enumeration is stopped.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "c")
+//	g.EnumInLinks("c", func(k string) bool {
+//		fmt.Println(k) // Will print "a"
+//		return true
+//	})
+func (self *Graph[K]) EnumInLinks(key K, f func(key K) bool) {
+	self.adj.enumInLinks(key, f)
+}
+
+// AddNode adds key as a node without links if it does not exist and returns
+// if it was added.
+//
+// Arguments:
+//
+//	key: The key to add.
+//
+// Returns:
+//
+//	added: True if the key was added, false if it already existed.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	added := g.AddNode("a") // added will be true
+//	added = g.AddNode("a")  // added will be false
+func (self *Graph[K]) AddNode(key K) (added bool) {
+	return self.adj.addNode(key)
+}
+
+// RemoveNode removes key and all links from and to it and returns if the
+// key existed.
+//
+// Arguments:
+//
+//	key: The key to remove.
+//
+// Returns:
+//
+//	existed: True if the key existed prior to this call, false otherwise.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "b")
+//	existed := g.RemoveNode("b") // existed will be true
+//	linked := g.Linked("a", "b") // linked will be false
+func (self *Graph[K]) RemoveNode(key K) (existed bool) {
+	return self.adj.removeNode(key)
+}
+
+// HasNode returns if key is a node of the graph.
+func (self *Graph[K]) HasNode(key K) (exists bool) {
+	return self.adj.hasNode(key)
+}
+
+// NodeCount returns the number of nodes in the graph.
+func (self *Graph[K]) NodeCount() int { return len(self.adj.links) }
+
+// EdgeCount returns the number of links in the graph. A link of an
+// undirected graph is counted once.
+func (self *Graph[K]) EdgeCount() int { return self.adj.edges }
+
+// OutDegree returns the number of keys a key links to.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "b")
+//	g.Link("a", "c")
+//	n := g.OutDegree("a") // n will be 2
+func (self *Graph[K]) OutDegree(key K) int { return self.adj.outDegree(key) }
+
+// InDegree returns the number of keys that link to a key. In an undirected
+// graph it is the same as [Graph.OutDegree].
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "c")
+//	g.Link("b", "c")
+//	n := g.InDegree("c") // n will be 2
+func (self *Graph[K]) InDegree(key K) int { return self.adj.inDegree(key) }
+
+// Nodes returns all nodes of the graph.
+//
+// Returns:
+//
+//	out: A slice containing all nodes. The order is not guaranteed.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "b")
+//	g.AddNode("c")
+//	nodes := g.Nodes() // nodes will be []string{"a", "b", "c"} (order not guaranteed)
+func (self *Graph[K]) Nodes() (out []K) {
+	return self.adj.nodes()
+}
+
+// EnumNodes calls f for each node of the graph. It keeps calling f until all
+// nodes have been enumerated or f returns false.
+func (self *Graph[K]) EnumNodes(f func(key K) bool) {
+	self.adj.enumNodes(f)
+}
+
+// Edges returns an iterator over all links of the graph as pairs of keys.
+// A link of an undirected graph is yielded once, in either direction.
+// The graph must not be modified during iteration.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "b")
+//	for a, b := range g.Edges() {
+//		fmt.Println(a, b) // Will print "a b"
+//	}
+func (self *Graph[K]) Edges() iter.Seq2[K, K] {
+	return func(yield func(K, K) bool) {
+		self.adj.enumEdges(func(a, b K, _ struct{}) bool { return yield(a, b) })
 	}
 }
 
@@ -163,7 +352,7 @@ type SyncGraph[K comparable] struct {
 	graph *Graph[K]
 }
 
-// NewSyncGraph returns a new concurrency safe [Graph].
+// NewSyncGraph returns a new concurrency safe directed [Graph].
 //
 // Example:
 //
@@ -174,6 +363,23 @@ func NewSyncGraph[K comparable]() *SyncGraph[K] {
 	}
 }
 
+// NewSyncUndirectedGraph returns a new concurrency safe undirected [Graph].
+//
+// Example:
+//
+//	g := NewSyncUndirectedGraph[int]()
+func NewSyncUndirectedGraph[K comparable]() *SyncGraph[K] {
+	return &SyncGraph[K]{
+		graph: NewUndirectedGraph[K](),
+	}
+}
+
+// Mode returns the mode of the graph.
+func (self *SyncGraph[K]) Mode() Mode { return self.graph.Mode() }
+
+// Directed returns true if the graph is directed.
+func (self *SyncGraph[K]) Directed() bool { return self.graph.Directed() }
+
 // Link links a and b if not already linked and returns if a and b were already
 // linked prior to this call.  This method is concurrency safe.
 //
@@ -294,3 +500,154 @@ func (self *SyncGraph[K]) EnumLinks(key K, f func(key K) bool) {
 	self.mu.Unlock()
 	return
 }
+
+// InLinks returns all keys that link to a key. This method is concurrency
+// safe.
+//
+// Arguments:
+//
+//	key: The key to get the links to.
+//
+// Returns:
+//
+//	out: A slice containing all keys that link to 'key'.
+//
+// Example:
+//
+//	g := NewSyncGraph[string]()
+//	g.Link("a", "c")
+//	links := g.InLinks("c") // links will be []string{"a"}
+func (self *SyncGraph[K]) InLinks(key K) (out []K) {
+	self.mu.Lock()
+	out = self.graph.InLinks(key)
+	self.mu.Unlock()
+	return
+}
+
+// AddNode adds key as a node without links if it does not exist and returns
+// if it was added. This method is concurrency safe.
+//
+// Arguments:
+//
+//	key: The key to add.
+//
+// Returns:
+//
+//	added: True if the key was added, false if it already existed.
+func (self *SyncGraph[K]) AddNode(key K) (added bool) {
+	self.mu.Lock()
+	added = self.graph.AddNode(key)
+	self.mu.Unlock()
+	return
+}
+
+// RemoveNode removes key and all links from and to it and returns if the
+// key existed. This method is concurrency safe.
+//
+// Arguments:
+//
+//	key: The key to remove.
+//
+// Returns:
+//
+//	existed: True if the key existed prior to this call, false otherwise.
+func (self *SyncGraph[K]) RemoveNode(key K) (existed bool) {
+	self.mu.Lock()
+	existed = self.graph.RemoveNode(key)
+	self.mu.Unlock()
+	return
+}
+
+// HasNode returns if key is a node of the graph. This method is concurrency
+// safe.
+func (self *SyncGraph[K]) HasNode(key K) (exists bool) {
+	self.mu.Lock()
+	exists = self.graph.HasNode(key)
+	self.mu.Unlock()
+	return
+}
+
+// NodeCount returns the number of nodes in the graph. This method is
+// concurrency safe.
+func (self *SyncGraph[K]) NodeCount() (out int) {
+	self.mu.Lock()
+	out = self.graph.NodeCount()
+	self.mu.Unlock()
+	return
+}
+
+// EdgeCount returns the number of links in the graph. This method is
+// concurrency safe.
+func (self *SyncGraph[K]) EdgeCount() (out int) {
+	self.mu.Lock()
+	out = self.graph.EdgeCount()
+	self.mu.Unlock()
+	return
+}
+
+// OutDegree returns the number of keys a key links to. This method is
+// concurrency safe.
+func (self *SyncGraph[K]) OutDegree(key K) (out int) {
+	self.mu.Lock()
+	out = self.graph.OutDegree(key)
+	self.mu.Unlock()
+	return
+}
+
+// InDegree returns the number of keys that link to a key. This method is
+// concurrency safe.
+func (self *SyncGraph[K]) InDegree(key K) (out int) {
+	self.mu.Lock()
+	out = self.graph.InDegree(key)
+	self.mu.Unlock()
+	return
+}
+
+// Nodes returns all nodes of the graph. This method is concurrency safe.
+//
+// Returns:
+//
+//	out: A slice containing all nodes. The order is not guaranteed.
+func (self *SyncGraph[K]) Nodes() (out []K) {
+	self.mu.Lock()
+	out = self.graph.Nodes()
+	self.mu.Unlock()
+	return
+}
+
+// EnumNodes calls f for each node of the graph. It keeps calling f until all
+// nodes have been enumerated or f returns false. This method is concurrency
+// safe. The graph is locked during enumeration so f must not call methods
+// of the graph.
+func (self *SyncGraph[K]) EnumNodes(f func(key K) bool) {
+	self.mu.Lock()
+	self.graph.EnumNodes(f)
+	self.mu.Unlock()
+}
+
+// Edges returns an iterator over all links of the graph as pairs of keys.
+// This method is concurrency safe. The graph is locked during iteration so
+// the loop body must not call methods of the graph.
+func (self *SyncGraph[K]) Edges() iter.Seq2[K, K] {
+	return func(yield func(K, K) bool) {
+		self.mu.Lock()
+		defer self.mu.Unlock()
+		self.graph.Edges()(yield)
+	}
+}
+
+// EnumInLinks calls f for each key that links to a key. It keeps calling f
+// until all links have been enumerated or f returns false. This method is
+// concurrency safe. The graph is locked during enumeration so f must not
+// call methods of the graph.
+//
+// Arguments:
+//
+//	key: The key to enumerate links to.
+//	f:   The function to call for each key linking to 'key'.
+//	     If the function returns false, the enumeration is stopped.
+func (self *SyncGraph[K]) EnumInLinks(key K, f func(key K) bool) {
+	self.mu.Lock()
+	self.graph.EnumInLinks(key, f)
+	self.mu.Unlock()
+}
@@ -2,6 +2,7 @@ package graph
 
 import (
 	"math/rand"
+	"slices"
 	"sync"
 	"testing"
 )
@@ -269,6 +270,170 @@ func TestSyncGraph(t *testing.T) {
 	})
 }
 
+func TestGraphModes(t *testing.T) {
+	t.Run("Directed", func(t *testing.T) {
+		g := NewGraph[string]()
+		if !g.Directed() || g.Mode() != Directed || g.Mode().String() != "directed" {
+			t.Fatal("NewGraph should be directed")
+		}
+		g.Link("a", "c")
+		g.Link("b", "c")
+		g.Link("c", "a")
+		in := g.InLinks("c")
+		slices.Sort(in)
+		if !slices.Equal(in, []string{"a", "b"}) {
+			t.Errorf("InLinks(c) should be [a b], got %v", in)
+		}
+		if in := g.InLinks("a"); !slices.Equal(in, []string{"c"}) {
+			t.Errorf("InLinks(a) should be [c], got %v", in)
+		}
+		g.Unlink("a", "c")
+		if in := g.InLinks("c"); !slices.Equal(in, []string{"b"}) {
+			t.Errorf("InLinks(c) after Unlink should be [b], got %v", in)
+		}
+		if !g.Linked("c", "a") {
+			t.Error("Unlink(a, c) should not unlink c -> a")
+		}
+		if in := g.InLinks("x"); len(in) != 0 {
+			t.Errorf("InLinks(x) should be empty, got %v", in)
+		}
+	})
+
+	t.Run("Undirected", func(t *testing.T) {
+		g := NewUndirectedGraph[int]()
+		if g.Directed() || g.Mode().String() != "undirected" {
+			t.Fatal("NewUndirectedGraph should be undirected")
+		}
+		if g.Link(1, 2) {
+			t.Error("Link(1, 2) should return false the first time")
+		}
+		if !g.Linked(2, 1) || !g.Linked(1, 2) {
+			t.Error("1 and 2 should be linked both ways")
+		}
+		if !g.Link(2, 1) {
+			t.Error("Link(2, 1) should report the existing link")
+		}
+		g.Link(1, 1)
+		if !g.Linked(1, 1) {
+			t.Error("self link should be linked")
+		}
+		if in := g.InLinks(2); !slices.Equal(in, []int{1}) {
+			t.Errorf("InLinks(2) should be [1], got %v", in)
+		}
+		if !g.Unlink(2, 1) {
+			t.Error("Unlink(2, 1) should return true")
+		}
+		if g.Linked(1, 2) || g.Linked(2, 1) {
+			t.Error("1 and 2 should be unlinked both ways")
+		}
+	})
+
+	t.Run("Sync", func(t *testing.T) {
+		g := NewSyncUndirectedGraph[int]()
+		g.Link(1, 2)
+		if g.Directed() || !g.Linked(2, 1) {
+			t.Error("sync undirected graph should link both ways")
+		}
+		d := NewSyncGraph[int]()
+		d.Link(1, 2)
+		var in []int
+		d.EnumInLinks(2, func(k int) bool {
+			in = append(in, k)
+			return true
+		})
+		if !slices.Equal(in, []int{1}) || !slices.Equal(d.InLinks(2), []int{1}) {
+			t.Errorf("InLinks(2) should be [1], got %v", in)
+		}
+	})
+}
+
+func TestGraphNodes(t *testing.T) {
+	countEdges := func(g *Graph[int]) (n int) {
+		for range g.Edges() {
+			n++
+		}
+		return
+	}
+	for _, mode := range []Mode{Directed, Undirected} {
+		t.Run(mode.String(), func(t *testing.T) {
+			g := NewGraph[int]()
+			if mode == Undirected {
+				g = NewUndirectedGraph[int]()
+			}
+			g.Link(1, 2)
+			g.Link(2, 3)
+			g.Link(3, 1)
+			g.Link(3, 3)
+			if !g.AddNode(4) || g.AddNode(4) {
+				t.Error("AddNode(4) should add once")
+			}
+			nodes := g.Nodes()
+			slices.Sort(nodes)
+			if !slices.Equal(nodes, []int{1, 2, 3, 4}) || g.NodeCount() != 4 {
+				t.Errorf("Nodes() should be [1 2 3 4], got %v", nodes)
+			}
+			if g.EdgeCount() != 4 || countEdges(g) != 4 {
+				t.Errorf("EdgeCount() should be 4, got %d, Edges() yields %d", g.EdgeCount(), countEdges(g))
+			}
+			wantOut, wantIn := 2, 2
+			if mode == Undirected {
+				wantOut, wantIn = 3, 3
+			}
+			if g.OutDegree(3) != wantOut || g.InDegree(3) != wantIn {
+				t.Errorf("degrees of 3 should be %d/%d, got %d/%d", wantOut, wantIn, g.OutDegree(3), g.InDegree(3))
+			}
+
+			if !g.RemoveNode(3) || g.RemoveNode(3) {
+				t.Error("RemoveNode(3) should remove once")
+			}
+			if g.HasNode(3) || g.Linked(2, 3) || g.Linked(3, 1) {
+				t.Error("links of 3 should be removed")
+			}
+			if g.EdgeCount() != 1 || countEdges(g) != 1 {
+				t.Errorf("EdgeCount() should be 1, got %d, Edges() yields %d", g.EdgeCount(), countEdges(g))
+			}
+			if g.InDegree(1) != len(g.InLinks(1)) || g.OutDegree(2) != len(g.Links(2)) {
+				t.Error("degrees should match links")
+			}
+			for a, b := range g.Edges() {
+				if a == 3 || b == 3 {
+					t.Errorf("Edges() should not yield removed node, got %d %d", a, b)
+				}
+			}
+			g.Unlink(1, 2)
+			if g.EdgeCount() != 0 || g.NodeCount() != 3 {
+				t.Errorf("Unlink should keep nodes, got %d edges %d nodes", g.EdgeCount(), g.NodeCount())
+			}
+		})
+	}
+
+	t.Run("Sync", func(t *testing.T) {
+		g := NewSyncGraph[int]()
+		g.Link(1, 2)
+		g.AddNode(3)
+		if g.NodeCount() != 3 || g.EdgeCount() != 1 || !g.HasNode(3) {
+			t.Error("unexpected counts")
+		}
+		if g.OutDegree(1) != 1 || g.InDegree(2) != 1 {
+			t.Error("unexpected degrees")
+		}
+		for a, b := range g.Edges() {
+			if a != 1 || b != 2 {
+				t.Errorf("Edges() should yield 1 2, got %d %d", a, b)
+			}
+		}
+		var n int
+		g.EnumNodes(func(int) bool { n++; return true })
+		if n != 3 || len(g.Nodes()) != 3 {
+			t.Errorf("should enumerate 3 nodes, got %d", n)
+		}
+		g.RemoveNode(1)
+		if g.EdgeCount() != 0 || g.InDegree(2) != 0 {
+			t.Error("RemoveNode(1) should remove its links")
+		}
+	})
+}
+
 func BenchmarkGraph_Link(b *testing.B) {
 	g := NewGraph[int]()
 	b.ResetTimer()
@@ -0,0 +1,296 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"container/heap"
+	"errors"
+)
+
+var (
+	// ErrNoPath is returned when no path leads from one key to another.
+	ErrNoPath = errors.New("no path")
+	// ErrNegativeWeight is returned by [Dijkstra] and [AStar] when a link
+	// has a negative weight.
+	ErrNegativeWeight = errors.New("negative weight")
+)
+
+// Number is a numeric type usable as a link weight.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Topology is a graph that enumerates its nodes and their links. It is
+// implemented by [Graph], [SyncGraph] and [ValueGraph].
+type Topology[K comparable] interface {
+	Adjacency[K]
+	// EnumNodes calls f for each node until f returns false.
+	EnumNodes(f func(key K) bool)
+}
+
+// ShortestPath returns the path with the fewest links from a key to another
+// found by breadth-first search. The path includes both keys.
+//
+// Arguments:
+//
+//	g:    The graph to search.
+//	from: The key to start from.
+//	to:   The key to reach.
+//
+// Returns:
+//
+//	path: Keys of the path from 'from' to 'to'.
+//	err:  [ErrNoPath] if 'to' is not reachable from 'from'.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "b")
+//	g.Link("b", "c")
+//	g.Link("a", "c")
+//	path, err := ShortestPath[string](g, "a", "c") // path will be []string{"a", "c"}
+func ShortestPath[K comparable](g Adjacency[K], from, to K) (path []K, err error) {
+	var prev = make(map[K]K)
+	for v := range BFS(g, nil, from) {
+		if v.Depth > 0 {
+			prev[v.Key] = v.Parent
+		}
+		if v.Key == to {
+			return buildPath(prev, from, to), nil
+		}
+	}
+	return nil, ErrNoPath
+}
+
+// Dijkstra returns the path with the lowest total weight from a key to
+// another and its cost using Dijkstra's algorithm. The path includes both
+// keys.
+//
+// Arguments:
+//
+//	g:      The graph to search.
+//	from:   The key to start from.
+//	to:     The key to reach.
+//	weight: Returns the weight of the link from a to b. Weights must not be negative.
+//
+// Returns:
+//
+//	path: Keys of the path from 'from' to 'to'.
+//	cost: The sum of the weights of the links of the path.
+//	err:  [ErrNoPath] if 'to' is not reachable from 'from' or
+//	      [ErrNegativeWeight] if a negative weight was encountered.
+//
+// Example:
+//
+//	g := NewValueGraph[string, int, struct{}]()
+//	g.LinkWith("a", "b", 1)
+//	g.LinkWith("b", "c", 1)
+//	g.LinkWith("a", "c", 5)
+//	path, cost, err := Dijkstra[string](g, "a", "c", EdgeWeight(g)) // path will be []string{"a", "b", "c"}, cost 2
+func Dijkstra[K comparable, W Number](g Adjacency[K], from, to K, weight func(a, b K) W) (path []K, cost W, err error) {
+	return search(g, from, to, weight, nil)
+}
+
+// AStar returns the path with the lowest total weight from a key to another
+// and its cost using the A* algorithm guided by heuristic. The path includes
+// both keys.
+//
+// The heuristic estimates the cost from a key to 'to'. It must never
+// overestimate the cost and must be consistent, i.e. the estimate for a key
+// must not exceed the weight of a link from it plus the estimate for the
+// linked key, otherwise the returned path may not be the shortest. A
+// heuristic that always returns 0 makes AStar equal to [Dijkstra].
+//
+// Arguments:
+//
+//	g:         The graph to search.
+//	from:      The key to start from.
+//	to:        The key to reach.
+//	weight:    Returns the weight of the link from a to b. Weights must not be negative.
+//	heuristic: Returns the estimated cost from a key to 'to'.
+//
+// Returns:
+//
+//	path: Keys of the path from 'from' to 'to'.
+//	cost: The sum of the weights of the links of the path.
+//	err:  [ErrNoPath] if 'to' is not reachable from 'from' or
+//	      [ErrNegativeWeight] if a negative weight was encountered.
+//
+// Example:
+//
+//	path, cost, err := AStar[Point](g, start, goal, distance, func(p Point) float64 {
+//		return distance(p, goal)
+//	})
+func AStar[K comparable, W Number](g Adjacency[K], from, to K, weight func(a, b K) W, heuristic func(key K) W) (path []K, cost W, err error) {
+	return search(g, from, to, weight, heuristic)
+}
+
+// EdgeWeight returns a weight function for [Dijkstra] and [AStar] that
+// returns the values of links of g.
+//
+// Example:
+//
+//	g := NewValueGraph[string, float64, struct{}]()
+//	path, cost, err := Dijkstra[string](g, "a", "b", EdgeWeight(g))
+func EdgeWeight[K comparable, W Number, N any](g *ValueGraph[K, W, N]) func(a, b K) W {
+	return func(a, b K) (w W) {
+		w, _ = g.Edge(a, b)
+		return
+	}
+}
+
+// Reachable returns all keys reachable from a key, including the key
+// itself.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "b")
+//	g.Link("c", "a")
+//	keys := Reachable[string](g, "a") // keys will be []string{"a", "b"}
+func Reachable[K comparable](g Adjacency[K], from K) (out []K) {
+	for v := range BFS(g, nil, from) {
+		out = append(out, v.Key)
+	}
+	return
+}
+
+// Reachability returns for each node of g the set of keys reachable from
+// it, including the node itself. It runs a breadth-first search from every
+// node and is intended for small graphs.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "b")
+//	r := Reachability[string](g)
+//	_, ok := r["a"]["b"] // ok will be true
+//	_, ok = r["b"]["a"]  // ok will be false
+func Reachability[K comparable](g Topology[K]) (out map[K]map[K]struct{}) {
+	out = make(map[K]map[K]struct{})
+	for _, node := range collectNodes(g) {
+		var set = make(map[K]struct{})
+		for v := range BFS(g, nil, node) {
+			set[v.Key] = struct{}{}
+		}
+		out[node] = set
+	}
+	return
+}
+
+// Distances returns for each node of g the number of links on the shortest
+// path to each key reachable from it, 0 for the node itself. It runs a
+// breadth-first search from every node and is intended for small graphs.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "b")
+//	g.Link("b", "c")
+//	d := Distances[string](g)
+//	n := d["a"]["c"] // n will be 2
+func Distances[K comparable](g Topology[K]) (out map[K]map[K]int) {
+	out = make(map[K]map[K]int)
+	for _, node := range collectNodes(g) {
+		var dist = make(map[K]int)
+		for v := range BFS(g, nil, node) {
+			dist[v.Key] = v.Depth
+		}
+		out[node] = dist
+	}
+	return
+}
+
+// search implements [Dijkstra] and, with a non-nil heuristic, [AStar].
+func search[K comparable, W Number](g Adjacency[K], from, to K, weight func(a, b K) W, heuristic func(key K) W) (path []K, cost W, err error) {
+	var (
+		dist  = map[K]W{from: 0}
+		prev  = make(map[K]K)
+		done  = make(map[K]struct{})
+		open  = &pathHeap[K, W]{}
+		links []K
+	)
+	var estimate = func(key K) (w W) {
+		if heuristic != nil {
+			w = heuristic(key)
+		}
+		return
+	}
+	heap.Push(open, pathItem[K, W]{key: from, priority: estimate(from)})
+	for open.Len() > 0 {
+		var item = heap.Pop(open).(pathItem[K, W])
+		if _, ok := done[item.key]; ok {
+			continue
+		}
+		if item.key == to {
+			return buildPath(prev, from, to), item.cost, nil
+		}
+		done[item.key] = struct{}{}
+		links = collectLinks(g, item.key, links[:0])
+		for _, key := range links {
+			var w = weight(item.key, key)
+			if w < 0 {
+				return nil, 0, ErrNegativeWeight
+			}
+			if _, ok := done[key]; ok {
+				continue
+			}
+			var c = item.cost + w
+			if d, ok := dist[key]; ok && c >= d {
+				continue
+			}
+			dist[key], prev[key] = c, item.key
+			heap.Push(open, pathItem[K, W]{key: key, cost: c, priority: c + estimate(key)})
+		}
+	}
+	return nil, 0, ErrNoPath
+}
+
+// buildPath returns the path from 'from' to 'to' following prev links back
+// from 'to'.
+func buildPath[K comparable](prev map[K]K, from, to K) (path []K) {
+	path = append(path, to)
+	for key := to; key != from; {
+		key = prev[key]
+		path = append(path, key)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return
+}
+
+// collectNodes returns all nodes of g.
+func collectNodes[K comparable](g Topology[K]) (out []K) {
+	g.EnumNodes(func(key K) bool {
+		out = append(out, key)
+		return true
+	})
+	return
+}
+
+// pathItem is a key reached by [search] at cost with priority cost plus
+// estimate.
+type pathItem[K comparable, W Number] struct {
+	key      K
+	cost     W
+	priority W
+}
+
+// pathHeap is a min heap of pathItem by priority.
+type pathHeap[K comparable, W Number] []pathItem[K, W]
+
+func (self pathHeap[K, W]) Len() int           { return len(self) }
+func (self pathHeap[K, W]) Less(i, j int) bool { return self[i].priority < self[j].priority }
+func (self pathHeap[K, W]) Swap(i, j int)      { self[i], self[j] = self[j], self[i] }
+func (self *pathHeap[K, W]) Push(x any)        { *self = append(*self, x.(pathItem[K, W])) }
+func (self *pathHeap[K, W]) Pop() (x any) {
+	var old = *self
+	x = old[len(old)-1]
+	*self = old[:len(old)-1]
+	return
+}
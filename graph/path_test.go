@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestShortestPath(t *testing.T) {
+	g := NewGraph[string]()
+	g.Link("a", "b")
+	g.Link("b", "c")
+	g.Link("c", "d")
+	g.Link("a", "c")
+	g.Link("e", "a")
+
+	path, err := ShortestPath[string](g, "a", "d")
+	if err != nil || !slices.Equal(path, []string{"a", "c", "d"}) {
+		t.Errorf("ShortestPath(a, d) should be [a c d], got %v, %v", path, err)
+	}
+	path, err = ShortestPath[string](g, "a", "a")
+	if err != nil || !slices.Equal(path, []string{"a"}) {
+		t.Errorf("ShortestPath(a, a) should be [a], got %v, %v", path, err)
+	}
+	if _, err = ShortestPath[string](g, "a", "e"); !errors.Is(err, ErrNoPath) {
+		t.Errorf("ShortestPath(a, e) should fail with ErrNoPath, got %v", err)
+	}
+}
+
+func TestDijkstra(t *testing.T) {
+	g := NewValueGraph[string, int, struct{}]()
+	g.LinkWith("a", "b", 1)
+	g.LinkWith("b", "c", 2)
+	g.LinkWith("a", "c", 5)
+	g.LinkWith("c", "d", 1)
+	g.LinkWith("b", "d", 7)
+	g.AddNode("x")
+
+	path, cost, err := Dijkstra[string](g, "a", "d", EdgeWeight(g))
+	if err != nil || cost != 4 || !slices.Equal(path, []string{"a", "b", "c", "d"}) {
+		t.Errorf("Dijkstra(a, d) should be [a b c d] at 4, got %v at %d, %v", path, cost, err)
+	}
+	if _, _, err = Dijkstra[string](g, "a", "x", EdgeWeight(g)); !errors.Is(err, ErrNoPath) {
+		t.Errorf("Dijkstra(a, x) should fail with ErrNoPath, got %v", err)
+	}
+	g.LinkWith("c", "d", -1)
+	if _, _, err = Dijkstra[string](g, "a", "d", EdgeWeight(g)); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("Dijkstra should fail with ErrNegativeWeight, got %v", err)
+	}
+
+	// Unweighted graphs work with a constant weight.
+	u := NewUndirectedGraph[int]()
+	for i := range 9 {
+		u.Link(i, i+1)
+	}
+	u.Link(0, 9)
+	upath, hops, err := Dijkstra[int](u, 2, 8, func(a, b int) uint { return 1 })
+	if err != nil || hops != 4 || !slices.Equal(upath, []int{2, 1, 0, 9, 8}) {
+		t.Errorf("Dijkstra(2, 8) should be [2 1 0 9 8] at 4, got %v at %d, %v", upath, hops, err)
+	}
+}
+
+func TestAStar(t *testing.T) {
+	// A 10x10 grid with a wall at x == 5 except at y == 9.
+	type point struct{ x, y int }
+	g := NewUndirectedGraph[point]()
+	for x := range 10 {
+		for y := range 10 {
+			if x == 5 && y != 9 {
+				continue
+			}
+			if x+1 < 10 && (x+1 != 5 || y == 9) {
+				g.Link(point{x, y}, point{x + 1, y})
+			}
+			if y+1 < 10 && x != 5 {
+				g.Link(point{x, y}, point{x, y + 1})
+			}
+		}
+	}
+	goal := point{9, 0}
+	manhattan := func(p point) float64 {
+		return math.Abs(float64(p.x-goal.x)) + math.Abs(float64(p.y-goal.y))
+	}
+	weight := func(a, b point) float64 { return 1 }
+	path, cost, err := AStar[point](g, point{0, 0}, goal, weight, manhattan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost != 27 || len(path) != 28 {
+		t.Errorf("AStar should find a path of cost 27, got %g with %d keys", cost, len(path))
+	}
+	_, dcost, _ := Dijkstra[point](g, point{0, 0}, goal, weight)
+	if dcost != cost {
+		t.Errorf("AStar cost %g should equal Dijkstra cost %g", cost, dcost)
+	}
+	for i := 1; i < len(path); i++ {
+		if !g.Linked(path[i-1], path[i]) {
+			t.Fatalf("path keys %v and %v are not linked", path[i-1], path[i])
+		}
+	}
+}
+
+func TestReachability(t *testing.T) {
+	g := NewGraph[string]()
+	g.Link("a", "b")
+	g.Link("b", "c")
+	g.Link("d", "a")
+
+	keys := Reachable[string](g, "b")
+	slices.Sort(keys)
+	if !slices.Equal(keys, []string{"b", "c"}) {
+		t.Errorf("Reachable(b) should be [b c], got %v", keys)
+	}
+	r := Reachability[string](g)
+	if len(r) != 4 || len(r["d"]) != 4 || len(r["c"]) != 1 {
+		t.Errorf("unexpected reachability %v", r)
+	}
+	if _, ok := r["a"]["d"]; ok {
+		t.Error("d should not be reachable from a")
+	}
+	d := Distances[string](g)
+	if d["d"]["c"] != 3 || d["a"]["a"] != 0 {
+		t.Errorf("unexpected distances %v", d)
+	}
+	if _, ok := d["c"]["a"]; ok {
+		t.Error("a should not have a distance from c")
+	}
+}
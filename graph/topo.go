@@ -0,0 +1,282 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"container/heap"
+	"fmt"
+	"strings"
+
+	"github.com/vedranvuk/ds/queue"
+)
+
+// CycleError is returned by topological sorts of a graph that contains a
+// cycle.
+type CycleError[K comparable] struct {
+	// Path is a cycle found in the graph. It starts and ends with the same
+	// key, each key links to the next.
+	Path []K
+}
+
+// Error implements error.
+func (self *CycleError[K]) Error() string {
+	var parts = make([]string, len(self.Path))
+	for i, key := range self.Path {
+		parts[i] = fmt.Sprint(key)
+	}
+	return "cycle " + strings.Join(parts, " -> ")
+}
+
+// TopoSort returns nodes of g in topological order where a key comes before
+// all keys it links to, e.g. a task before tasks that depend on it. The
+// order of keys that do not depend on each other is not defined.
+//
+// Sorting is meaningful for [Directed] graphs, every link of an undirected
+// graph is a cycle.
+//
+// Arguments:
+//
+//	g: The graph to sort.
+//
+// Returns:
+//
+//	out: Nodes of g in topological order.
+//	err: A [*CycleError] describing a cycle if g is not acyclic.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("fetch", "build")
+//	g.Link("build", "test")
+//	order, err := TopoSort[string](g) // order will be []string{"fetch", "build", "test"}
+func TopoSort[K comparable](g Topology[K]) (out []K, err error) {
+	var (
+		nodes, indeg = inDegrees(g)
+		ready        = queue.New[K]()
+		links        []K
+	)
+	for _, key := range nodes {
+		if indeg[key] == 0 {
+			ready.Push(key)
+		}
+	}
+	out = make([]K, 0, len(nodes))
+	for {
+		var key, ok = ready.Pop()
+		if !ok {
+			break
+		}
+		out = append(out, key)
+		links = collectLinks(g, key, links[:0])
+		for _, next := range links {
+			if indeg[next]--; indeg[next] == 0 {
+				ready.Push(next)
+			}
+		}
+	}
+	if len(out) < len(nodes) {
+		return nil, findCycle(g, indeg)
+	}
+	return
+}
+
+// TopoSortFunc returns nodes of g in topological order like [TopoSort] but
+// whenever several keys are ready it picks the one that compares lowest by
+// cmp, which makes the order deterministic. Passing cmp.Compare gives the
+// lexicographically smallest order, a cmp of task priorities schedules
+// important tasks first.
+//
+// Arguments:
+//
+//	g:   The graph to sort.
+//	cmp: Compares keys, returning a negative number if a should come before b.
+//
+// Returns:
+//
+//	out: Nodes of g in topological order.
+//	err: A [*CycleError] describing a cycle if g is not acyclic.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("b", "c")
+//	g.AddNode("a")
+//	order, err := TopoSortFunc[string](g, cmp.Compare[string]) // order will be []string{"a", "b", "c"}
+func TopoSortFunc[K comparable](g Topology[K], cmp func(a, b K) int) (out []K, err error) {
+	var (
+		nodes, indeg = inDegrees(g)
+		ready        = &keyHeap[K]{cmp: cmp}
+		links        []K
+	)
+	for _, key := range nodes {
+		if indeg[key] == 0 {
+			ready.keys = append(ready.keys, key)
+		}
+	}
+	heap.Init(ready)
+	out = make([]K, 0, len(nodes))
+	for ready.Len() > 0 {
+		var key = heap.Pop(ready).(K)
+		out = append(out, key)
+		links = collectLinks(g, key, links[:0])
+		for _, next := range links {
+			if indeg[next]--; indeg[next] == 0 {
+				heap.Push(ready, next)
+			}
+		}
+	}
+	if len(out) < len(nodes) {
+		return nil, findCycle(g, indeg)
+	}
+	return
+}
+
+// TopoLevels returns nodes of g grouped in topological levels. The first
+// level holds keys no key links to and each following level holds keys
+// linked to only from keys of previous levels. Keys of a level do not depend
+// on each other and can be processed in parallel once previous levels are
+// done. The order of keys within a level is not defined.
+//
+// Arguments:
+//
+//	g: The graph to sort.
+//
+// Returns:
+//
+//	out: Levels of nodes in topological order.
+//	err: A [*CycleError] describing a cycle if g is not acyclic.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("fetch", "build")
+//	g.Link("fetch", "lint")
+//	g.Link("build", "test")
+//	levels, err := TopoLevels[string](g) // levels will be [[fetch] [build lint] [test]]
+func TopoLevels[K comparable](g Topology[K]) (out [][]K, err error) {
+	var (
+		nodes, indeg = inDegrees(g)
+		level        []K
+		links        []K
+		done         int
+	)
+	for _, key := range nodes {
+		if indeg[key] == 0 {
+			level = append(level, key)
+		}
+	}
+	for len(level) > 0 {
+		out = append(out, level)
+		done += len(level)
+		var next []K
+		for _, key := range level {
+			links = collectLinks(g, key, links[:0])
+			for _, link := range links {
+				if indeg[link]--; indeg[link] == 0 {
+					next = append(next, link)
+				}
+			}
+		}
+		level = next
+	}
+	if done < len(nodes) {
+		return nil, findCycle(g, indeg)
+	}
+	return
+}
+
+// inDegrees returns all nodes of g, including keys only linked to, and the
+// number of links to each.
+func inDegrees[K comparable](g Topology[K]) (nodes []K, indeg map[K]int) {
+	indeg = make(map[K]int)
+	g.EnumNodes(func(key K) bool {
+		if _, ok := indeg[key]; !ok {
+			indeg[key] = 0
+			nodes = append(nodes, key)
+		}
+		return true
+	})
+	for i := 0; i < len(nodes); i++ {
+		g.EnumLinks(nodes[i], func(key K) bool {
+			if _, ok := indeg[key]; !ok {
+				nodes = append(nodes, key)
+			}
+			indeg[key]++
+			return true
+		})
+	}
+	return
+}
+
+// findCycle returns a [*CycleError] of a cycle among keys with a positive
+// in-degree left by a topological sort. Every such key is linked to from
+// another such key so they always contain a cycle.
+func findCycle[K comparable](g Topology[K], indeg map[K]int) *CycleError[K] {
+	// frame is a key on the current search path.
+	type frame struct {
+		key   K
+		links []K
+		next  int
+	}
+	const (
+		onPath = 1
+		done   = 2
+	)
+	var state = make(map[K]int)
+	for start, n := range indeg {
+		if n == 0 || state[start] != 0 {
+			continue
+		}
+		var path = []*frame{{key: start, links: collectLinks(g, start, nil)}}
+		state[start] = onPath
+		for len(path) > 0 {
+			var top = path[len(path)-1]
+			if top.next == len(top.links) {
+				state[top.key] = done
+				path = path[:len(path)-1]
+				continue
+			}
+			var key = top.links[top.next]
+			top.next++
+			if indeg[key] == 0 {
+				continue
+			}
+			switch state[key] {
+			case onPath:
+				var out = &CycleError[K]{}
+				for i := len(path) - 1; i >= 0; i-- {
+					if path[i].key == key {
+						for _, f := range path[i:] {
+							out.Path = append(out.Path, f.key)
+						}
+						break
+					}
+				}
+				out.Path = append(out.Path, key)
+				return out
+			case 0:
+				state[key] = onPath
+				path = append(path, &frame{key: key, links: collectLinks(g, key, nil)})
+			}
+		}
+	}
+	return &CycleError[K]{}
+}
+
+// keyHeap is a min heap of keys ordered by cmp.
+type keyHeap[K comparable] struct {
+	keys []K
+	cmp  func(a, b K) int
+}
+
+func (self keyHeap[K]) Len() int           { return len(self.keys) }
+func (self keyHeap[K]) Less(i, j int) bool { return self.cmp(self.keys[i], self.keys[j]) < 0 }
+func (self keyHeap[K]) Swap(i, j int)      { self.keys[i], self.keys[j] = self.keys[j], self.keys[i] }
+func (self *keyHeap[K]) Push(x any)        { self.keys = append(self.keys, x.(K)) }
+func (self *keyHeap[K]) Pop() (x any) {
+	x = self.keys[len(self.keys)-1]
+	self.keys = self.keys[:len(self.keys)-1]
+	return
+}
@@ -0,0 +1,114 @@
+package graph
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// checkTopo fails t if order is not a topological order of all nodes of g.
+func checkTopo(t *testing.T, g *Graph[string], order []string) {
+	t.Helper()
+	if len(order) != g.NodeCount() {
+		t.Fatalf("order should hold %d nodes, got %v", g.NodeCount(), order)
+	}
+	for a, b := range g.Edges() {
+		if slices.Index(order, a) > slices.Index(order, b) {
+			t.Fatalf("%s should come before %s in %v", a, b, order)
+		}
+	}
+}
+
+// checkCycle fails t if err is not a CycleError with a valid cycle of g.
+func checkCycle(t *testing.T, g *Graph[string], err error) {
+	t.Helper()
+	var ce *CycleError[string]
+	if !errors.As(err, &ce) {
+		t.Fatalf("should fail with CycleError, got %v", err)
+	}
+	if len(ce.Path) < 2 || ce.Path[0] != ce.Path[len(ce.Path)-1] {
+		t.Fatalf("cycle should start and end with the same key, got %v", ce.Path)
+	}
+	for i := 1; i < len(ce.Path); i++ {
+		if !g.Linked(ce.Path[i-1], ce.Path[i]) {
+			t.Fatalf("%s should link to %s in cycle %v", ce.Path[i-1], ce.Path[i], ce.Path)
+		}
+	}
+}
+
+func buildGraph() *Graph[string] {
+	g := NewGraph[string]()
+	g.Link("fetch", "build")
+	g.Link("fetch", "lint")
+	g.Link("build", "test")
+	g.Link("lint", "test")
+	g.Link("test", "release")
+	g.Link("docs", "release")
+	return g
+}
+
+func TestTopoSort(t *testing.T) {
+	g := buildGraph()
+	order, err := TopoSort[string](g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTopo(t, g, order)
+
+	if order, err = TopoSort[string](NewGraph[string]()); err != nil || len(order) != 0 {
+		t.Errorf("empty graph should sort to nothing, got %v, %v", order, err)
+	}
+
+	g.Link("release", "lint")
+	g.Link("x", "y")
+	_, err = TopoSort[string](g)
+	checkCycle(t, g, err)
+	if err.Error() == "" {
+		t.Error("CycleError should have a message")
+	}
+
+	self := NewGraph[string]()
+	self.Link("a", "a")
+	_, err = TopoSort[string](self)
+	checkCycle(t, self, err)
+}
+
+func TestTopoSortFunc(t *testing.T) {
+	g := buildGraph()
+	order, err := TopoSortFunc[string](g, cmp.Compare[string])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"docs", "fetch", "build", "lint", "test", "release"}
+	if !slices.Equal(order, want) {
+		t.Errorf("TopoSortFunc should be %v, got %v", want, order)
+	}
+	order, _ = TopoSortFunc[string](g, func(a, b string) int { return -cmp.Compare(a, b) })
+	want = []string{"fetch", "lint", "docs", "build", "test", "release"}
+	if !slices.Equal(order, want) {
+		t.Errorf("reverse TopoSortFunc should be %v, got %v", want, order)
+	}
+	g.Link("test", "fetch")
+	_, err = TopoSortFunc[string](g, cmp.Compare[string])
+	checkCycle(t, g, err)
+}
+
+func TestTopoLevels(t *testing.T) {
+	g := buildGraph()
+	levels, err := TopoLevels[string](g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, level := range levels {
+		slices.Sort(level)
+	}
+	want := [][]string{{"docs", "fetch"}, {"build", "lint"}, {"test"}, {"release"}}
+	if !slices.EqualFunc(levels, want, slices.Equal) {
+		t.Errorf("TopoLevels should be %v, got %v", want, levels)
+	}
+	g.Link("release", "docs")
+	g.Link("docs", "release")
+	_, err = TopoLevels[string](g)
+	checkCycle(t, g, err)
+}
@@ -0,0 +1,237 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"iter"
+
+	"github.com/vedranvuk/ds/queue"
+	"github.com/vedranvuk/ds/stack"
+)
+
+// Adjacency is a graph that enumerates the links of a key. It is
+// implemented by [Graph], [SyncGraph] and [ValueGraph].
+type Adjacency[K comparable] interface {
+	// EnumLinks calls f for each key that key links to until f returns
+	// false.
+	EnumLinks(key K, f func(key K) bool)
+}
+
+// Event is the kind of a [Visit].
+type Event int
+
+const (
+	// Pre is the visit of a key when it is first reached, before keys
+	// reached from it.
+	Pre Event = iota
+	// Post is the visit of a key after all keys reached from it were
+	// visited. Only [DFS] yields Post visits.
+	Post
+)
+
+// String returns the name of the event.
+func (self Event) String() string {
+	if self == Post {
+		return "post"
+	}
+	return "pre"
+}
+
+// Visit is a key reached by a traversal.
+type Visit[K comparable] struct {
+	// Key is the visited key.
+	Key K
+	// Parent is the key Key was reached from. It is the zero value for
+	// start keys.
+	Parent K
+	// Depth is the number of links from a start key to Key, 0 for start
+	// keys.
+	Depth int
+	// Event is the kind of the visit.
+	Event Event
+}
+
+// TraverseOptions configure [BFS] and [DFS].
+type TraverseOptions[K comparable] struct {
+	// MaxDepth is the maximum depth of visited keys. Keys at MaxDepth are
+	// visited but links from them are not followed. If <= 0 depth is not
+	// limited.
+	MaxDepth int
+	// Filter, if not nil, is called with the [Pre] visit of a key before it
+	// is visited. If it returns false the key is skipped and links from it
+	// are not followed, but it may still be reached through another key.
+	Filter func(v Visit[K]) bool
+	// Post enables [Post] visits in [DFS].
+	Post bool
+}
+
+// BFS returns an iterator that visits keys of g reachable from start keys in
+// breadth-first order. Each key is visited once with a [Pre] visit. A nil
+// opts visits all reachable keys.
+//
+// The iterator does not yield while enumerating links so it is safe to use
+// with [SyncGraph] and to stop early. g must not be modified during
+// iteration.
+//
+// Arguments:
+//
+//	g:     The graph to traverse.
+//	opts:  Traversal options, may be nil.
+//	start: Keys to start from, visited at depth 0 in order given.
+//
+// Returns:
+//
+//	An iterator of visits.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "b")
+//	g.Link("b", "c")
+//	for v := range BFS[string](g, &TraverseOptions[string]{MaxDepth: 1}, "a") {
+//		fmt.Println(v.Key, v.Depth) // Will print "a 0" and "b 1"
+//	}
+func BFS[K comparable](g Adjacency[K], opts *TraverseOptions[K], start ...K) iter.Seq[Visit[K]] {
+	var o = traverseOptions(opts)
+	return func(yield func(Visit[K]) bool) {
+		var (
+			seen    = make(map[K]struct{})
+			pending = queue.New[Visit[K]]()
+		)
+		var discover = func(v Visit[K]) {
+			if _, ok := seen[v.Key]; ok {
+				return
+			}
+			if o.Filter != nil && !o.Filter(v) {
+				return
+			}
+			seen[v.Key] = struct{}{}
+			pending.Push(v)
+		}
+		for _, key := range start {
+			discover(Visit[K]{Key: key})
+		}
+		var links []K
+		for {
+			var v, ok = pending.Pop()
+			if !ok {
+				return
+			}
+			if !yield(v) {
+				return
+			}
+			if o.MaxDepth > 0 && v.Depth >= o.MaxDepth {
+				continue
+			}
+			links = collectLinks(g, v.Key, links[:0])
+			for _, key := range links {
+				discover(Visit[K]{Key: key, Parent: v.Key, Depth: v.Depth + 1})
+			}
+		}
+	}
+}
+
+// DFS returns an iterator that visits keys of g reachable from start keys in
+// depth-first order. Each key is visited once with a [Pre] visit and, if
+// enabled by [TraverseOptions.Post], once with a [Post] visit after all keys
+// reached from it. A nil opts visits all reachable keys with Pre visits.
+//
+// The iterator does not yield while enumerating links so it is safe to use
+// with [SyncGraph] and to stop early. g must not be modified during
+// iteration.
+//
+// Arguments:
+//
+//	g:     The graph to traverse.
+//	opts:  Traversal options, may be nil.
+//	start: Keys to start from, visited at depth 0 in order given.
+//
+// Returns:
+//
+//	An iterator of visits.
+//
+// Example:
+//
+//	g := NewGraph[string]()
+//	g.Link("a", "b")
+//	g.Link("b", "c")
+//	for v := range DFS[string](g, &TraverseOptions[string]{Post: true}, "a") {
+//		fmt.Println(v.Event, v.Key) // Will print "pre a", "pre b", "pre c", "post c", "post b", "post a"
+//	}
+func DFS[K comparable](g Adjacency[K], opts *TraverseOptions[K], start ...K) iter.Seq[Visit[K]] {
+	var o = traverseOptions(opts)
+	// frame is a visited key whose links are being followed.
+	type frame struct {
+		visit Visit[K]
+		links []K
+		next  int
+	}
+	return func(yield func(Visit[K]) bool) {
+		var (
+			seen = make(map[K]struct{})
+			path = stack.New[*frame]()
+		)
+		// enter visits v if not seen or filtered and pushes its frame.
+		var enter = func(v Visit[K]) (ok bool) {
+			if _, ok := seen[v.Key]; ok {
+				return true
+			}
+			if o.Filter != nil && !o.Filter(v) {
+				return true
+			}
+			seen[v.Key] = struct{}{}
+			if !yield(v) {
+				return false
+			}
+			var f = &frame{visit: v}
+			if o.MaxDepth <= 0 || v.Depth < o.MaxDepth {
+				f.links = collectLinks(g, v.Key, nil)
+			}
+			path.Push(f)
+			return true
+		}
+		for _, key := range start {
+			if !enter(Visit[K]{Key: key}) {
+				return
+			}
+			for path.Size() > 0 {
+				var top = path.Peek()
+				if top.next < len(top.links) {
+					var key = top.links[top.next]
+					top.next++
+					if !enter(Visit[K]{Key: key, Parent: top.visit.Key, Depth: top.visit.Depth + 1}) {
+						return
+					}
+					continue
+				}
+				path.Pop()
+				if o.Post {
+					var v = top.visit
+					v.Event = Post
+					if !yield(v) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// traverseOptions returns opts or default options if opts is nil.
+func traverseOptions[K comparable](opts *TraverseOptions[K]) (out TraverseOptions[K]) {
+	if opts != nil {
+		out = *opts
+	}
+	return
+}
+
+// collectLinks appends links of key in g to out and returns it.
+func collectLinks[K comparable](g Adjacency[K], key K, out []K) []K {
+	g.EnumLinks(key, func(k K) bool {
+		out = append(out, k)
+		return true
+	})
+	return out
+}
@@ -0,0 +1,164 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// treeGraph returns a graph of a binary tree of keys 1 to n where key k
+// links to 2k and 2k+1.
+func treeGraph(n int) *Graph[int] {
+	g := NewGraph[int]()
+	for k := 2; k <= n; k++ {
+		g.Link(k/2, k)
+	}
+	return g
+}
+
+func collect[K comparable](seq func(func(Visit[K]) bool)) (out []Visit[K]) {
+	for v := range seq {
+		out = append(out, v)
+	}
+	return
+}
+
+func TestBFS(t *testing.T) {
+	g := treeGraph(15)
+	visits := collect(BFS[int](g, nil, 1))
+	if len(visits) != 15 {
+		t.Fatalf("BFS should visit 15 keys, got %d", len(visits))
+	}
+	for i, v := range visits {
+		if i > 0 && v.Depth < visits[i-1].Depth {
+			t.Fatalf("BFS depth should not decrease, got %v after %v", v, visits[i-1])
+		}
+		if v.Key > 1 && v.Parent != v.Key/2 {
+			t.Errorf("parent of %d should be %d, got %d", v.Key, v.Key/2, v.Parent)
+		}
+		if v.Event != Pre {
+			t.Errorf("BFS should only yield pre visits, got %v", v.Event)
+		}
+	}
+
+	// Depth limit.
+	visits = collect(BFS[int](g, &TraverseOptions[int]{MaxDepth: 2}, 1))
+	if len(visits) != 7 {
+		t.Errorf("BFS with MaxDepth 2 should visit 7 keys, got %d", len(visits))
+	}
+
+	// Filter skips a subtree.
+	visits = collect(BFS[int](g, &TraverseOptions[int]{
+		Filter: func(v Visit[int]) bool { return v.Key != 2 },
+	}, 1))
+	if len(visits) != 8 {
+		t.Errorf("BFS filtering 2 should visit 8 keys, got %d", len(visits))
+	}
+
+	// Multiple start keys and cycles.
+	c := NewGraph[string]()
+	c.Link("a", "b")
+	c.Link("b", "a")
+	c.Link("x", "y")
+	var keys []string
+	for v := range BFS[string](c, nil, "a", "x", "b") {
+		keys = append(keys, fmt.Sprintf("%s%d", v.Key, v.Depth))
+	}
+	if !slices.Equal(keys, []string{"a0", "x0", "b0", "y1"}) {
+		t.Errorf("BFS should yield [a0 x0 b0 y1], got %v", keys)
+	}
+
+	// Early termination.
+	n := 0
+	for range BFS[int](g, nil, 1) {
+		if n++; n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("BFS should stop after 3 visits, got %d", n)
+	}
+}
+
+func TestDFS(t *testing.T) {
+	g := NewGraph[string]()
+	g.Link("a", "b")
+	g.Link("b", "c")
+	g.Link("c", "a")
+	g.Link("c", "d")
+
+	var events []string
+	for v := range DFS[string](g, &TraverseOptions[string]{Post: true}, "a") {
+		events = append(events, v.Event.String()+" "+v.Key)
+	}
+	want := []string{"pre a", "pre b", "pre c", "pre d", "post d", "post c", "post b", "post a"}
+	if !slices.Equal(events, want) {
+		t.Errorf("DFS should yield %v, got %v", want, events)
+	}
+
+	// Pre visits only by default, each key once.
+	visits := collect(DFS[string](g, nil, "a", "c"))
+	if len(visits) != 4 {
+		t.Errorf("DFS should visit 4 keys, got %d", len(visits))
+	}
+
+	// Depth limit and filter.
+	visits = collect(DFS[string](g, &TraverseOptions[string]{MaxDepth: 1, Post: true}, "a"))
+	if len(visits) != 4 || visits[1].Key != "b" || visits[1].Depth != 1 {
+		t.Errorf("DFS with MaxDepth 1 should visit a and b, got %v", visits)
+	}
+	visits = collect(DFS[string](g, &TraverseOptions[string]{
+		Filter: func(v Visit[string]) bool { return v.Key != "c" },
+	}, "a"))
+	if len(visits) != 2 {
+		t.Errorf("DFS filtering c should visit 2 keys, got %v", visits)
+	}
+
+	// Post order of a tree visits children before parents.
+	tree := treeGraph(31)
+	done := map[int]bool{}
+	for v := range DFS[int](tree, &TraverseOptions[int]{Post: true}, 1) {
+		if v.Event != Post {
+			continue
+		}
+		for _, child := range []int{v.Key * 2, v.Key*2 + 1} {
+			if child <= 31 && !done[child] {
+				t.Fatalf("post visit of %d before child %d", v.Key, child)
+			}
+		}
+		done[v.Key] = true
+	}
+	if len(done) != 31 {
+		t.Errorf("DFS should post visit 31 keys, got %d", len(done))
+	}
+
+	// Early termination.
+	n := 0
+	for range DFS[int](tree, &TraverseOptions[int]{Post: true}, 1) {
+		if n++; n == 5 {
+			break
+		}
+	}
+	if n != 5 {
+		t.Errorf("DFS should stop after 5 visits, got %d", n)
+	}
+}
+
+func TestTraverseSyncGraph(t *testing.T) {
+	g := NewSyncGraph[int]()
+	for k := 2; k <= 7; k++ {
+		g.Link(k/2, k)
+	}
+	for v := range BFS[int](g, nil, 1) {
+		// The graph must not be locked while yielding.
+		g.Linked(v.Parent, v.Key)
+	}
+	if n := len(collect(DFS[int](g, nil, 1))); n != 7 {
+		t.Errorf("DFS should visit 7 keys, got %d", n)
+	}
+	vg := NewUndirectedValueGraph[int, int, struct{}]()
+	vg.LinkWith(1, 2, 1)
+	if n := len(collect(BFS[int](vg, nil, 2))); n != 2 {
+		t.Errorf("BFS of ValueGraph should visit 2 keys, got %d", n)
+	}
+}
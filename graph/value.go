@@ -0,0 +1,355 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package graph
+
+import "iter"
+
+// ValueGraph is a [Graph] that stores a value of type E on each link and an
+// optional attribute of type N on each node, e.g. a latency or a label on a
+// dependency between services.
+//
+// Like [Graph] it is either [Directed] or [Undirected]. Both directions of
+// an undirected link share the same value.
+//
+// Example:
+//
+//	g := NewValueGraph[string, time.Duration, string]()
+//	g.LinkWith("api", "db", 5*time.Millisecond)
+//	g.SetNode("db", "postgres")
+//	latency, ok := g.Edge("api", "db") // latency will be 5ms, ok will be true
+//	label, ok := g.Node("db")          // label will be "postgres", ok will be true
+type ValueGraph[K comparable, E, N any] struct {
+	adj   adjacency[K, E]
+	attrs map[K]N
+}
+
+// NewValueGraph returns a new directed [ValueGraph] of keys K, link values
+// E and node attributes N.
+//
+// Example:
+//
+//	g := NewValueGraph[string, float64, struct{}]()
+func NewValueGraph[K comparable, E, N any]() *ValueGraph[K, E, N] {
+	return &ValueGraph[K, E, N]{
+		adj:   makeAdjacency[K, E](Directed),
+		attrs: make(map[K]N),
+	}
+}
+
+// NewUndirectedValueGraph returns a new undirected [ValueGraph] of keys K,
+// link values E and node attributes N.
+//
+// Example:
+//
+//	g := NewUndirectedValueGraph[string, float64, struct{}]()
+//	g.LinkWith("a", "b", 1.5)
+//	w, ok := g.Edge("b", "a") // w will be 1.5, ok will be true
+func NewUndirectedValueGraph[K comparable, E, N any]() *ValueGraph[K, E, N] {
+	return &ValueGraph[K, E, N]{
+		adj:   makeAdjacency[K, E](Undirected),
+		attrs: make(map[K]N),
+	}
+}
+
+// Mode returns the mode of the graph.
+func (self *ValueGraph[K, E, N]) Mode() Mode { return self.adj.mode }
+
+// Directed returns true if the graph is directed.
+func (self *ValueGraph[K, E, N]) Directed() bool { return self.adj.mode == Directed }
+
+// Link links a and b with a zero value if not already linked and returns if
+// a and b were already linked prior to this call. The value of an existing
+// link is not changed.
+//
+// Arguments:
+//
+//	a: The first key to link.
+//	b: The second key to link.
+//
+// Returns:
+//
+//	wasLinked: True if a and b were already linked prior to this call, false otherwise.
+func (self *ValueGraph[K, E, N]) Link(a, b K) (wasLinked bool) {
+	if wasLinked = self.adj.linked(a, b); !wasLinked {
+		self.adj.link(a, b, *new(E))
+	}
+	return
+}
+
+// LinkWith links a and b with value v, replacing the value of an existing
+// link, and returns if a and b were already linked prior to this call.
+//
+// Arguments:
+//
+//	a: The first key to link.
+//	b: The second key to link.
+//	v: The value of the link.
+//
+// Returns:
+//
+//	wasLinked: True if a and b were already linked prior to this call, false otherwise.
+//
+// Example:
+//
+//	g := NewValueGraph[string, int, struct{}]()
+//	wasLinked := g.LinkWith("a", "b", 1) // wasLinked will be false
+//	wasLinked = g.LinkWith("a", "b", 2)  // wasLinked will be true
+//	v, _ := g.Edge("a", "b")             // v will be 2
+func (self *ValueGraph[K, E, N]) LinkWith(a, b K, v E) (wasLinked bool) {
+	return self.adj.link(a, b, v)
+}
+
+// Unlink breaks the link between a and b if it exists and returns if the link
+// existed prior to this call.
+//
+// Arguments:
+//
+//	a: The first key to unlink.
+//	b: The second key to unlink.
+//
+// Returns:
+//
+//	wasLinked: True if the link between a and b existed prior to this call, false otherwise.
+func (self *ValueGraph[K, E, N]) Unlink(a, b K) (wasLinked bool) {
+	return self.adj.unlink(a, b)
+}
+
+// Linked returns if a and b are linked.
+//
+// Arguments:
+//
+//	a: The first key.
+//	b: The second key.
+//
+// Returns:
+//
+//	linked: True if a and b are linked, false otherwise.
+func (self *ValueGraph[K, E, N]) Linked(a, b K) (linked bool) {
+	return self.adj.linked(a, b)
+}
+
+// Edge returns the value of the link between a and b.
+//
+// Arguments:
+//
+//	a: The first key.
+//	b: The second key.
+//
+// Returns:
+//
+//	v:      The value of the link or a zero value if a and b are not linked.
+//	exists: True if a and b are linked, false otherwise.
+//
+// Example:
+//
+//	g := NewValueGraph[string, int, struct{}]()
+//	g.LinkWith("a", "b", 3)
+//	v, exists := g.Edge("a", "b") // v will be 3, exists will be true
+//	v, exists = g.Edge("b", "a")  // v will be 0, exists will be false
+func (self *ValueGraph[K, E, N]) Edge(a, b K) (v E, exists bool) {
+	return self.adj.edge(a, b)
+}
+
+// Links returns names of all keys a key links to.
+//
+// Arguments:
+//
+//	key: The key to get the links for.
+//
+// Returns:
+//
+//	out: A slice containing all keys that 'key' links to.
+func (self *ValueGraph[K, E, N]) Links(key K) (out []K) {
+	out = make([]K, 0, self.adj.outDegree(key))
+	self.EnumLinks(key, func(k K) bool {
+		out = append(out, k)
+		return true
+	})
+	return
+}
+
+// EnumLinks calls f for each link a key has. It keeps calling f until all links
+// have been enumerated or f returns false.
+//
+// Arguments:
+//
+//	key: The key to enumerate links for.
+//	f:   The function to call for each link. The function receives the linked key as argument.
+//	     If the function returns false, the enumeration is stopped.
+func (self *ValueGraph[K, E, N]) EnumLinks(key K, f func(key K) bool) {
+	self.adj.enumLinks(key, func(k K, _ E) bool { return f(k) })
+}
+
+// EnumLinkValues calls f for each link a key has with the value of the link.
+// It keeps calling f until all links have been enumerated or f returns false.
+//
+// Arguments:
+//
+//	key: The key to enumerate links for.
+//	f:   The function to call for each link. The function receives the linked key and the link value.
+//	     If the function returns false, the enumeration is stopped.
+//
+// Example:
+//
+//	g := NewValueGraph[string, int, struct{}]()
+//	g.LinkWith("a", "b", 1)
+//	g.EnumLinkValues("a", func(k string, v int) bool {
+//		fmt.Println(k, v) // Will print "b 1"
+//		return true
+//	})
+func (self *ValueGraph[K, E, N]) EnumLinkValues(key K, f func(key K, v E) bool) {
+	self.adj.enumLinks(key, f)
+}
+
+// InLinks returns all keys that link to a key. In an undirected graph these
+// are the same as [ValueGraph.Links].
+//
+// Arguments:
+//
+//	key: The key to get the links to.
+//
+// Returns:
+//
+//	out: A slice containing all keys that link to 'key'.
+func (self *ValueGraph[K, E, N]) InLinks(key K) (out []K) {
+	out = make([]K, 0, self.adj.inDegree(key))
+	self.EnumInLinks(key, func(k K) bool {
+		out = append(out, k)
+		return true
+	})
+	return
+}
+
+// EnumInLinks calls f for each key that links to a key. It keeps calling f
+// until all links have been enumerated or f returns false.
+//
+// Arguments:
+//
+//	key: The key to enumerate links to.
+//	f:   The function to call for each key linking to 'key'.
+//	     If the function returns false, the enumeration is stopped.
+func (self *ValueGraph[K, E, N]) EnumInLinks(key K, f func(key K) bool) {
+	self.adj.enumInLinks(key, f)
+}
+
+// AddNode adds key as a node without links if it does not exist and returns
+// if it was added.
+//
+// Arguments:
+//
+//	key: The key to add.
+//
+// Returns:
+//
+//	added: True if the key was added, false if it already existed.
+func (self *ValueGraph[K, E, N]) AddNode(key K) (added bool) {
+	return self.adj.addNode(key)
+}
+
+// SetNode sets the attribute of node key to n, adding the node if it does
+// not exist.
+//
+// Arguments:
+//
+//	key: The key of the node.
+//	n:   The node attribute.
+//
+// Example:
+//
+//	g := NewValueGraph[string, int, string]()
+//	g.SetNode("db", "postgres")
+//	label, ok := g.Node("db") // label will be "postgres", ok will be true
+func (self *ValueGraph[K, E, N]) SetNode(key K, n N) {
+	self.adj.addNode(key)
+	self.attrs[key] = n
+}
+
+// Node returns the attribute of node key.
+//
+// Arguments:
+//
+//	key: The key of the node.
+//
+// Returns:
+//
+//	n:  The node attribute or a zero value if it was not set.
+//	ok: True if the attribute was set with [ValueGraph.SetNode], false otherwise.
+func (self *ValueGraph[K, E, N]) Node(key K) (n N, ok bool) {
+	n, ok = self.attrs[key]
+	return
+}
+
+// RemoveNode removes key, its attribute and all links from and to it and
+// returns if the key existed.
+//
+// Arguments:
+//
+//	key: The key to remove.
+//
+// Returns:
+//
+//	existed: True if the key existed prior to this call, false otherwise.
+func (self *ValueGraph[K, E, N]) RemoveNode(key K) (existed bool) {
+	delete(self.attrs, key)
+	return self.adj.removeNode(key)
+}
+
+// HasNode returns if key is a node of the graph.
+func (self *ValueGraph[K, E, N]) HasNode(key K) (exists bool) {
+	return self.adj.hasNode(key)
+}
+
+// NodeCount returns the number of nodes in the graph.
+func (self *ValueGraph[K, E, N]) NodeCount() int { return len(self.adj.links) }
+
+// EdgeCount returns the number of links in the graph. A link of an
+// undirected graph is counted once.
+func (self *ValueGraph[K, E, N]) EdgeCount() int { return self.adj.edges }
+
+// OutDegree returns the number of keys a key links to.
+func (self *ValueGraph[K, E, N]) OutDegree(key K) int { return self.adj.outDegree(key) }
+
+// InDegree returns the number of keys that link to a key.
+func (self *ValueGraph[K, E, N]) InDegree(key K) int { return self.adj.inDegree(key) }
+
+// Nodes returns all nodes of the graph.
+//
+// Returns:
+//
+//	out: A slice containing all nodes. The order is not guaranteed.
+func (self *ValueGraph[K, E, N]) Nodes() (out []K) {
+	return self.adj.nodes()
+}
+
+// EnumNodes calls f for each node of the graph. It keeps calling f until all
+// nodes have been enumerated or f returns false.
+func (self *ValueGraph[K, E, N]) EnumNodes(f func(key K) bool) {
+	self.adj.enumNodes(f)
+}
+
+// Edges returns an iterator over all links of the graph as pairs of keys.
+// A link of an undirected graph is yielded once, in either direction.
+// The graph must not be modified during iteration.
+func (self *ValueGraph[K, E, N]) Edges() iter.Seq2[K, K] {
+	return func(yield func(K, K) bool) {
+		self.adj.enumEdges(func(a, b K, _ E) bool { return yield(a, b) })
+	}
+}
+
+// EnumEdges calls f for each link of the graph with the value of the link.
+// It keeps calling f until all links have been enumerated or f returns
+// false. A link of an undirected graph is enumerated once.
+//
+// Example:
+//
+//	g := NewValueGraph[string, int, struct{}]()
+//	g.LinkWith("a", "b", 1)
+//	g.EnumEdges(func(a, b string, v int) bool {
+//		fmt.Println(a, b, v) // Will print "a b 1"
+//		return true
+//	})
+func (self *ValueGraph[K, E, N]) EnumEdges(f func(a, b K, v E) bool) {
+	self.adj.enumEdges(f)
+}
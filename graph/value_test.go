@@ -0,0 +1,128 @@
+package graph
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValueGraph(t *testing.T) {
+	t.Run("Directed", func(t *testing.T) {
+		g := NewValueGraph[string, int, string]()
+		if g.LinkWith("a", "b", 1) {
+			t.Error("LinkWith should return false the first time")
+		}
+		if !g.LinkWith("a", "b", 2) {
+			t.Error("LinkWith should return true the second time")
+		}
+		if v, ok := g.Edge("a", "b"); !ok || v != 2 {
+			t.Errorf("Edge(a, b) should be (2, true), got (%d, %t)", v, ok)
+		}
+		if _, ok := g.Edge("b", "a"); ok {
+			t.Error("Edge(b, a) should not exist")
+		}
+		if g.Link("a", "b") {
+			if v, _ := g.Edge("a", "b"); v != 2 {
+				t.Errorf("Link should keep the value of an existing link, got %d", v)
+			}
+		} else {
+			t.Error("Link(a, b) should report the existing link")
+		}
+		g.Link("a", "c")
+		if v, ok := g.Edge("a", "c"); !ok || v != 0 {
+			t.Errorf("Edge(a, c) should be (0, true), got (%d, %t)", v, ok)
+		}
+		if g.EdgeCount() != 2 || g.NodeCount() != 3 {
+			t.Errorf("counts should be 2 edges, 3 nodes, got %d, %d", g.EdgeCount(), g.NodeCount())
+		}
+
+		g.SetNode("b", "postgres")
+		g.SetNode("d", "orphan")
+		if n, ok := g.Node("b"); !ok || n != "postgres" {
+			t.Errorf("Node(b) should be (postgres, true), got (%s, %t)", n, ok)
+		}
+		if _, ok := g.Node("a"); ok {
+			t.Error("Node(a) should not have an attribute")
+		}
+		if !g.HasNode("d") || g.NodeCount() != 4 {
+			t.Error("SetNode should add the node")
+		}
+
+		links := g.Links("a")
+		slices.Sort(links)
+		if !slices.Equal(links, []string{"b", "c"}) {
+			t.Errorf("Links(a) should be [b c], got %v", links)
+		}
+		if in := g.InLinks("b"); !slices.Equal(in, []string{"a"}) || g.InDegree("b") != 1 {
+			t.Errorf("InLinks(b) should be [a], got %v", in)
+		}
+		sum := 0
+		g.EnumLinkValues("a", func(k string, v int) bool {
+			sum += v
+			return true
+		})
+		if sum != 2 {
+			t.Errorf("link values of a should sum to 2, got %d", sum)
+		}
+
+		g.RemoveNode("b")
+		if _, ok := g.Node("b"); ok || g.Linked("a", "b") || g.EdgeCount() != 1 {
+			t.Error("RemoveNode(b) should remove the attribute and links")
+		}
+		if !g.Unlink("a", "c") || g.EdgeCount() != 0 || g.OutDegree("a") != 0 {
+			t.Error("Unlink(a, c) should remove the last link")
+		}
+	})
+
+	t.Run("Undirected", func(t *testing.T) {
+		g := NewUndirectedValueGraph[int, float64, struct{}]()
+		if g.Directed() || g.Mode() != Undirected {
+			t.Fatal("graph should be undirected")
+		}
+		g.LinkWith(1, 2, 1.5)
+		g.LinkWith(2, 3, 2.5)
+		g.LinkWith(3, 2, 3.5)
+		if v, ok := g.Edge(2, 3); !ok || v != 3.5 {
+			t.Errorf("Edge(2, 3) should be (3.5, true), got (%g, %t)", v, ok)
+		}
+		if v, _ := g.Edge(2, 1); v != 1.5 {
+			t.Errorf("Edge(2, 1) should be 1.5, got %g", v)
+		}
+		var total float64
+		var n int
+		g.EnumEdges(func(a, b int, v float64) bool {
+			total += v
+			n++
+			return true
+		})
+		if n != 2 || total != 5 || g.EdgeCount() != 2 {
+			t.Errorf("EnumEdges should yield 2 links totalling 5, got %d totalling %g", n, total)
+		}
+		n = 0
+		for range g.Edges() {
+			n++
+		}
+		if n != 2 {
+			t.Errorf("Edges() should yield 2 links, got %d", n)
+		}
+		var nodes []int
+		g.EnumNodes(func(k int) bool {
+			nodes = append(nodes, k)
+			return true
+		})
+		slices.Sort(nodes)
+		if !slices.Equal(nodes, []int{1, 2, 3}) || len(g.Nodes()) != 3 {
+			t.Errorf("EnumNodes should yield [1 2 3], got %v", nodes)
+		}
+		var in []int
+		g.EnumInLinks(1, func(k int) bool {
+			in = append(in, k)
+			return true
+		})
+		if !slices.Equal(in, []int{2}) || !g.Linked(1, 2) {
+			t.Errorf("EnumInLinks(1) should yield [2], got %v", in)
+		}
+		if !g.AddNode(4) || g.AddNode(4) {
+			t.Error("AddNode(4) should add once")
+		}
+	})
+}
@@ -0,0 +1,278 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrFull is returned when an item is pushed to a full bounded queue whose
+// [OverflowPolicy] is [Reject].
+var ErrFull = errors.New("queue full")
+
+// OverflowPolicy defines what a bounded queue does when an item is pushed
+// while the queue is at capacity.
+type OverflowPolicy int
+
+const (
+	// Block makes Push wait until there is room in the queue.
+	Block OverflowPolicy = iota
+	// Reject makes Push return [ErrFull] and discard the pushed item.
+	Reject
+	// DropNewest discards the pushed item without an error.
+	DropNewest
+	// DropOldest discards the item at the front of the queue to make room
+	// for the pushed item.
+	DropOldest
+)
+
+// String returns the name of the policy.
+func (self OverflowPolicy) String() string {
+	switch self {
+	case Block:
+		return "block"
+	case Reject:
+		return "reject"
+	case DropNewest:
+		return "drop newest"
+	case DropOldest:
+		return "drop oldest"
+	}
+	return "unknown"
+}
+
+// OverflowStats counts what happened to items pushed to a full bounded queue.
+type OverflowStats struct {
+	// Blocked is the number of pushes that had to wait for room.
+	Blocked uint64
+	// Rejected is the number of pushed items rejected with [ErrFull].
+	Rejected uint64
+	// DroppedNewest is the number of pushed items silently discarded.
+	DroppedNewest uint64
+	// DroppedOldest is the number of queued items discarded to make room.
+	DroppedOldest uint64
+}
+
+// Dropped returns the total number of items lost to overflow.
+func (self OverflowStats) Dropped() uint64 {
+	return self.Rejected + self.DroppedNewest + self.DroppedOldest
+}
+
+// BoundedQueue is a [Queue] that holds at most a fixed number of items and
+// handles overflow according to an [OverflowPolicy].
+//
+// BoundedQueue is not safe for concurrent use so nothing can make room while
+// a Push waits; the [Block] policy therefore behaves like [Reject]. Use
+// [BoundedSyncQueue] for blocking producers.
+//
+// Example:
+//
+//	q := NewBounded[int](2, DropOldest)
+//	q.Push(1)
+//	q.Push(2)
+//	q.Push(3)
+//	v, ok := q.Pop() // v == 2, ok == true
+type BoundedQueue[V any] struct {
+	q        *Queue[V]
+	capacity int
+	policy   OverflowPolicy
+	stats    OverflowStats
+}
+
+// NewBounded returns a new [BoundedQueue] of V that holds at most capacity
+// items and handles overflow according to policy. A capacity < 1 is treated
+// as 1.
+//
+// Example:
+//
+//	q := NewBounded[int](100, Reject)
+func NewBounded[V any](capacity int, policy OverflowPolicy) *BoundedQueue[V] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &BoundedQueue[V]{
+		q:        New[V](),
+		capacity: capacity,
+		policy:   policy,
+	}
+}
+
+// Cap returns the maximum number of items the queue holds.
+func (self *BoundedQueue[V]) Cap() int { return self.capacity }
+
+// Len returns the number of items in the queue.
+func (self *BoundedQueue[V]) Len() int { return self.q.Len() }
+
+// Policy returns the overflow policy of the queue.
+func (self *BoundedQueue[V]) Policy() OverflowPolicy { return self.policy }
+
+// Stats returns the overflow counters of the queue.
+//
+// Example:
+//
+//	q := NewBounded[int](1, DropNewest)
+//	q.Push(1)
+//	q.Push(2)
+//	n := q.Stats().DroppedNewest // n == 1
+func (self *BoundedQueue[V]) Stats() OverflowStats { return self.stats }
+
+// Push pushes v to end of queue. If the queue is full v is handled according
+// to the queue policy and ErrFull is returned if the policy is [Reject] or
+// [Block].
+//
+// Example:
+//
+//	q := NewBounded[int](1, Reject)
+//	err := q.Push(1) // err == nil
+//	err = q.Push(2)  // err == ErrFull
+func (self *BoundedQueue[V]) Push(v V) (err error) {
+	if self.q.Len() < self.capacity {
+		self.q.Push(v)
+		return nil
+	}
+	switch self.policy {
+	case DropNewest:
+		self.stats.DroppedNewest++
+	case DropOldest:
+		self.q.Pop()
+		self.q.Push(v)
+		self.stats.DroppedOldest++
+	default:
+		self.stats.Rejected++
+		err = ErrFull
+	}
+	return
+}
+
+// Pop returns item from the start of the queue and truth if one was found.
+// Returned value should be ignored if truth if false, it is the zero value of
+// Queue generic type.
+//
+// Example:
+//
+//	q := NewBounded[int](1, Reject)
+//	q.Push(1)
+//	v, ok := q.Pop() // v == 1, ok == true
+func (self *BoundedQueue[V]) Pop() (v V, b bool) { return self.q.Pop() }
+
+// BoundedSyncQueue is the concurrency safe version of [BoundedQueue].
+// With the [Block] policy Push waits until a Pop makes room in the queue.
+//
+// Example:
+//
+//	q := NewBoundedSync[int](1, Block)
+//	q.Push(1)
+//	go q.Pop()
+//	q.Push(2) // waits until 1 is popped
+type BoundedSyncQueue[V any] struct {
+	mu sync.Mutex
+	q  *BoundedQueue[V]
+	// space is closed and replaced each time an item is popped so that
+	// blocked pushes can retry.
+	space chan struct{}
+}
+
+// NewBoundedSync returns a new [BoundedSyncQueue] of V that holds at most
+// capacity items and handles overflow according to policy. A capacity < 1
+// is treated as 1.
+//
+// Example:
+//
+//	q := NewBoundedSync[int](100, Block)
+func NewBoundedSync[V any](capacity int, policy OverflowPolicy) *BoundedSyncQueue[V] {
+	return &BoundedSyncQueue[V]{
+		q:     NewBounded[V](capacity, policy),
+		space: make(chan struct{}),
+	}
+}
+
+// Cap returns the maximum number of items the queue holds.
+func (self *BoundedSyncQueue[V]) Cap() int { return self.q.Cap() }
+
+// Len returns the number of items in the queue.
+func (self *BoundedSyncQueue[V]) Len() (l int) {
+	self.mu.Lock()
+	l = self.q.Len()
+	self.mu.Unlock()
+	return
+}
+
+// Policy returns the overflow policy of the queue.
+func (self *BoundedSyncQueue[V]) Policy() OverflowPolicy { return self.q.Policy() }
+
+// Stats returns the overflow counters of the queue.
+func (self *BoundedSyncQueue[V]) Stats() (stats OverflowStats) {
+	self.mu.Lock()
+	stats = self.q.Stats()
+	self.mu.Unlock()
+	return
+}
+
+// Push pushes v to end of queue. If the queue is full v is handled according
+// to the queue policy. With the [Block] policy Push waits for room
+// indefinitely, see [BoundedSyncQueue.PushContext].
+//
+// Example:
+//
+//	q := NewBoundedSync[int](1, Reject)
+//	err := q.Push(1) // err == nil
+//	err = q.Push(2)  // err == ErrFull
+func (self *BoundedSyncQueue[V]) Push(v V) (err error) {
+	return self.PushContext(context.Background(), v)
+}
+
+// PushContext is like [BoundedSyncQueue.Push] but a push blocked by the
+// [Block] policy gives up and returns ctx.Err() when ctx is done.
+//
+// Example:
+//
+//	q := NewBoundedSync[int](1, Block)
+//	q.Push(1)
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//	defer cancel()
+//	err := q.PushContext(ctx, 2) // err == context.DeadlineExceeded
+func (self *BoundedSyncQueue[V]) PushContext(ctx context.Context, v V) (err error) {
+	self.mu.Lock()
+	if self.q.policy != Block || self.q.Len() < self.q.Cap() {
+		err = self.q.Push(v)
+		self.mu.Unlock()
+		return
+	}
+	self.q.stats.Blocked++
+	for self.q.Len() >= self.q.Cap() {
+		var space = self.space
+		self.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-space:
+		}
+		self.mu.Lock()
+	}
+	err = self.q.Push(v)
+	self.mu.Unlock()
+	return
+}
+
+// Pop returns item from the start of the queue and truth if one was found.
+// Returned value should be ignored if truth if false, it is the zero value of
+// Queue generic type.
+//
+// Example:
+//
+//	q := NewBoundedSync[int](1, Block)
+//	q.Push(1)
+//	v, ok := q.Pop() // v == 1, ok == true
+func (self *BoundedSyncQueue[V]) Pop() (v V, b bool) {
+	self.mu.Lock()
+	if v, b = self.q.Pop(); b {
+		close(self.space)
+		self.space = make(chan struct{})
+	}
+	self.mu.Unlock()
+	return
+}
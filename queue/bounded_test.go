@@ -0,0 +1,119 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBoundedQueuePolicies(t *testing.T) {
+	for _, test := range []struct {
+		policy OverflowPolicy
+		err    error
+		items  []int
+		stats  OverflowStats
+	}{
+		{Block, ErrFull, []int{1, 2}, OverflowStats{Rejected: 1}},
+		{Reject, ErrFull, []int{1, 2}, OverflowStats{Rejected: 1}},
+		{DropNewest, nil, []int{1, 2}, OverflowStats{DroppedNewest: 1}},
+		{DropOldest, nil, []int{2, 3}, OverflowStats{DroppedOldest: 1}},
+	} {
+		t.Run(test.policy.String(), func(t *testing.T) {
+			q := NewBounded[int](2, test.policy)
+			if err := q.Push(1); err != nil {
+				t.Fatal(err)
+			}
+			if err := q.Push(2); err != nil {
+				t.Fatal(err)
+			}
+			if err := q.Push(3); err != test.err {
+				t.Fatalf("expected %v, got %v", test.err, err)
+			}
+			if l := q.Len(); l != 2 {
+				t.Fatalf("expected len 2, got %d", l)
+			}
+			if stats := q.Stats(); stats != test.stats {
+				t.Fatalf("expected %+v, got %+v", test.stats, stats)
+			}
+			for _, want := range test.items {
+				if v, ok := q.Pop(); !ok || v != want {
+					t.Fatalf("expected (%d, true), got (%d, %t)", want, v, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestBoundedQueueCapacity(t *testing.T) {
+	q := NewBounded[int](0, Reject)
+	if c := q.Cap(); c != 1 {
+		t.Fatalf("expected cap 1, got %d", c)
+	}
+}
+
+func TestBoundedSyncQueueBlock(t *testing.T) {
+	q := NewBoundedSync[int](1, Block)
+	if err := q.Push(1); err != nil {
+		t.Fatal(err)
+	}
+
+	var done = make(chan error)
+	go func() { done <- q.Push(2) }()
+
+	select {
+	case err := <-done:
+		t.Fatalf("push did not block, err: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if v, ok := q.Pop(); !ok || v != 1 {
+		t.Fatalf("expected (1, true), got (%d, %t)", v, ok)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := q.Pop(); !ok || v != 2 {
+		t.Fatalf("expected (2, true), got (%d, %t)", v, ok)
+	}
+	if blocked := q.Stats().Blocked; blocked != 1 {
+		t.Fatalf("expected 1 blocked push, got %d", blocked)
+	}
+}
+
+func TestBoundedSyncQueuePushContext(t *testing.T) {
+	q := NewBoundedSync[int](1, Block)
+	q.Push(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.PushContext(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if l := q.Len(); l != 1 {
+		t.Fatalf("expected len 1, got %d", l)
+	}
+}
+
+func TestBoundedSyncQueueDropOldest(t *testing.T) {
+	q := NewBoundedSync[int](3, DropOldest)
+	for i := 0; i < 10; i++ {
+		if err := q.Push(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if dropped := q.Stats().Dropped(); dropped != 7 {
+		t.Fatalf("expected 7 dropped, got %d", dropped)
+	}
+	for _, want := range []int{7, 8, 9} {
+		if v, ok := q.Pop(); !ok || v != want {
+			t.Fatalf("expected (%d, true), got (%d, %t)", want, v, ok)
+		}
+	}
+}
+
+func BenchmarkBoundedSyncQueueDropOldest(b *testing.B) {
+	q := NewBoundedSync[int](1024, DropOldest)
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package queue
+
+import "context"
+
+// Out returns a channel that receives items popped from the queue in queue
+// order, so the queue can be consumed in a select statement.
+//
+// Out starts a goroutine that pops items as the channel is read and waits
+// for pushes while the queue is empty. When ctx is done the goroutine
+// returns an item it popped but could not deliver to the front of the
+// queue and closes the channel, so no item is lost.
+//
+// Example:
+//
+//	q := NewSync[int]()
+//	out := q.Out(ctx)
+//	q.Push(1)
+//	select {
+//	case v := <-out: // v == 1
+//	case <-time.After(time.Second):
+//	}
+func (self *SyncQueue[V]) Out(ctx context.Context) <-chan V {
+	var out = make(chan V)
+	go func() {
+		defer close(out)
+		for {
+			self.mu.Lock()
+			var v, ok = self.q.Pop()
+			if !ok {
+				var wait = self.waiter()
+				self.mu.Unlock()
+				select {
+				case <-ctx.Done():
+					return
+				case <-wait:
+				}
+				continue
+			}
+			self.mu.Unlock()
+
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				self.mu.Lock()
+				self.q.unpop(v)
+				self.notify()
+				self.mu.Unlock()
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// In returns a channel whose received values are pushed to the queue, so
+// the queue can be fed from a select statement, and a channel that is
+// closed once the feeding goroutine exits.
+//
+// The goroutine exits when in is closed or ctx is done. When ctx is done it
+// first pushes the values of senders already blocked on in. Values sent
+// after done is closed are never received, so senders should also select on
+// ctx.Done().
+//
+// Example:
+//
+//	q := NewSync[int]()
+//	in, done := q.In(ctx)
+//	in <- 1
+//	close(in)
+//	<-done
+//	v, ok := q.Pop() // v == 1, ok == true
+func (self *SyncQueue[V]) In(ctx context.Context) (in chan<- V, done <-chan struct{}) {
+	var c = make(chan V)
+	var d = make(chan struct{})
+	go func() {
+		defer close(d)
+		for {
+			select {
+			case v, ok := <-c:
+				if !ok {
+					return
+				}
+				self.Push(v)
+			case <-ctx.Done():
+				for {
+					select {
+					case v, ok := <-c:
+						if !ok {
+							return
+						}
+						self.Push(v)
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+	return c, d
+}
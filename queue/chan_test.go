@@ -0,0 +1,108 @@
+package queue
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSyncQueueOut(t *testing.T) {
+	q := NewSync[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q.PushMany(1, 2)
+	out := q.Out(ctx)
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		q.Push(3)
+	}()
+	for want := 1; want <= 3; want++ {
+		select {
+		case v := <-out:
+			if v != want {
+				t.Fatalf("expected %d, got %d", want, v)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out")
+		}
+	}
+}
+
+func TestSyncQueueOutCancelKeepsItems(t *testing.T) {
+	q := NewSync[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	q.PushMany(1, 2, 3)
+	out := q.Out(ctx)
+	if v := <-out; v != 1 {
+		t.Fatalf("expected 1, got %d", v)
+	}
+	// The goroutine now holds 2 waiting for a reader.
+	waitFor(t, func() bool { return q.Len() == 1 })
+	cancel()
+	for range out {
+		t.Fatal("unexpected item after cancel")
+	}
+	if items := q.PopN(10); !slices.Equal(items, []int{2, 3}) {
+		t.Fatalf("expected [2 3], got %v", items)
+	}
+}
+
+func TestSyncQueueOutCancelEmpty(t *testing.T) {
+	q := NewSync[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	out := q.Out(ctx)
+	cancel()
+	if _, ok := <-out; ok {
+		t.Fatal("expected closed channel")
+	}
+}
+
+func TestSyncQueueIn(t *testing.T) {
+	q := NewSync[int]()
+	in, done := q.In(context.Background())
+	for i := 0; i < 3; i++ {
+		in <- i
+	}
+	close(in)
+	<-done
+	if items := q.PopN(10); !slices.Equal(items, []int{0, 1, 2}) {
+		t.Fatalf("expected [0 1 2], got %v", items)
+	}
+}
+
+func TestSyncQueueInCancel(t *testing.T) {
+	q := NewSync[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	in, done := q.In(ctx)
+	in <- 1
+	cancel()
+	<-done
+	select {
+	case in <- 2:
+		t.Fatal("send succeeded after done")
+	default:
+	}
+	if items := q.PopN(10); !slices.Equal(items, []int{1}) {
+		t.Fatalf("expected [1], got %v", items)
+	}
+}
+
+func TestSyncQueueInOut(t *testing.T) {
+	q := NewSync[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in, _ := q.In(ctx)
+	out := q.Out(ctx)
+	go func() {
+		for i := 0; i < 100; i++ {
+			in <- i
+		}
+	}()
+	for want := 0; want < 100; want++ {
+		if v := <-out; v != want {
+			t.Fatalf("expected %d, got %d", want, v)
+		}
+	}
+}
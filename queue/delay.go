@@ -0,0 +1,247 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock is a source of time used by [DelayQueue].
+//
+// The default implementation, [SystemClock], uses the time package. Tests may
+// provide a fake implementation to control when items become due.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer returns a Timer that fires once after duration d.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single shot timer returned by [Clock.NewTimer].
+type Timer interface {
+	// C returns the channel on which the time is delivered when the timer
+	// fires.
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing. It returns false if the timer
+	// already fired or was stopped.
+	Stop() bool
+}
+
+// SystemClock is a [Clock] backed by the time package.
+type SystemClock struct{}
+
+// Now returns time.Now().
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// NewTimer returns a Timer backed by a time.Timer.
+func (SystemClock) NewTimer(d time.Duration) Timer { return systemTimer{time.NewTimer(d)} }
+
+// systemTimer adapts time.Timer to the [Timer] interface.
+type systemTimer struct{ t *time.Timer }
+
+func (self systemTimer) C() <-chan time.Time { return self.t.C }
+
+func (self systemTimer) Stop() bool { return self.t.Stop() }
+
+// DelayQueue is a concurrency safe queue whose items become visible to Pop
+// only after their deadline has passed. Items are popped in deadline order;
+// items with equal deadlines are popped in the order they were pushed.
+//
+// Example:
+//
+//	q := NewDelay[string](nil)
+//	q.Push("retry", time.Second)
+//	v, err := q.Pop(ctx) // blocks for about a second, v == "retry"
+type DelayQueue[V any] struct {
+	mu    sync.Mutex
+	clock Clock
+	items delayHeap[V]
+	seq   uint64
+	// wake is closed and replaced each time an item is pushed so that
+	// blocked Pop calls can recompute their wait.
+	wake chan struct{}
+}
+
+// NewDelay returns a new [DelayQueue] that reads time from clock.
+// If clock is nil [SystemClock] is used.
+//
+// Example:
+//
+//	q := NewDelay[int](nil)
+func NewDelay[V any](clock Clock) *DelayQueue[V] {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	return &DelayQueue[V]{
+		clock: clock,
+		wake:  make(chan struct{}),
+	}
+}
+
+// Len returns the number of items in the queue, due or not.
+//
+// Example:
+//
+//	q := NewDelay[int](nil)
+//	q.Push(1, time.Hour)
+//	l := q.Len() // l == 1
+func (self *DelayQueue[V]) Len() (l int) {
+	self.mu.Lock()
+	l = len(self.items)
+	self.mu.Unlock()
+	return
+}
+
+// Push pushes v to the queue to become poppable after delay elapses.
+// A delay <= 0 makes v due immediately.
+//
+// Example:
+//
+//	q := NewDelay[int](nil)
+//	q.Push(1, 5*time.Second)
+func (self *DelayQueue[V]) Push(v V, delay time.Duration) {
+	self.PushAt(v, self.clock.Now().Add(delay))
+}
+
+// PushAt pushes v to the queue to become poppable at time t.
+// A t that is not after the current time makes v due immediately.
+//
+// Example:
+//
+//	q := NewDelay[int](nil)
+//	q.PushAt(1, time.Now().Add(time.Minute))
+func (self *DelayQueue[V]) PushAt(v V, t time.Time) {
+	self.mu.Lock()
+	heap.Push(&self.items, delayItem[V]{value: v, at: t, seq: self.seq})
+	self.seq++
+	close(self.wake)
+	self.wake = make(chan struct{})
+	self.mu.Unlock()
+}
+
+// Next returns the deadline of the earliest item in the queue and truth if
+// the queue is not empty.
+//
+// Example:
+//
+//	q := NewDelay[int](nil)
+//	q.Push(1, time.Minute)
+//	t, ok := q.Next() // t == about a minute from now, ok == true
+func (self *DelayQueue[V]) Next() (t time.Time, ok bool) {
+	self.mu.Lock()
+	if ok = len(self.items) > 0; ok {
+		t = self.items[0].at
+	}
+	self.mu.Unlock()
+	return
+}
+
+// TryPop returns the earliest item if it is due and truth if one was
+// returned. It never blocks. Returned value should be ignored if truth is
+// false, it is the zero value of V.
+//
+// Example:
+//
+//	q := NewDelay[int](nil)
+//	q.Push(1, 0)
+//	v, ok := q.TryPop() // v == 1, ok == true
+func (self *DelayQueue[V]) TryPop() (v V, ok bool) {
+	self.mu.Lock()
+	v, ok, _, _ = self.popDue()
+	self.mu.Unlock()
+	return
+}
+
+// Pop blocks until the earliest item in the queue is due and returns it.
+// If ctx is done before an item becomes due Pop returns ctx.Err().
+//
+// Example:
+//
+//	q := NewDelay[int](nil)
+//	q.Push(1, time.Second)
+//	v, err := q.Pop(context.Background()) // v == 1, err == nil
+func (self *DelayQueue[V]) Pop(ctx context.Context) (v V, err error) {
+	for {
+		self.mu.Lock()
+		var (
+			ok, pending bool
+			wait        time.Duration
+		)
+		if v, ok, wait, pending = self.popDue(); ok {
+			self.mu.Unlock()
+			return
+		}
+		var wake = self.wake
+		self.mu.Unlock()
+
+		var timer Timer
+		var fired <-chan time.Time
+		if pending {
+			timer = self.clock.NewTimer(wait)
+			fired = timer.C()
+		}
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return v, ctx.Err()
+		case <-wake:
+		case <-fired:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// popDue pops the earliest item if it is due. If not, it returns the wait
+// until the earliest item is due and truth if the queue is not empty.
+// Caller must hold the lock.
+func (self *DelayQueue[V]) popDue() (v V, ok bool, wait time.Duration, pending bool) {
+	if len(self.items) == 0 {
+		return
+	}
+	if wait = self.items[0].at.Sub(self.clock.Now()); wait > 0 {
+		pending = true
+		return
+	}
+	return heap.Pop(&self.items).(delayItem[V]).value, true, 0, false
+}
+
+// delayItem is an item in a [DelayQueue].
+type delayItem[V any] struct {
+	value V
+	at    time.Time
+	seq   uint64
+}
+
+// delayHeap is a min heap of delayItem ordered by deadline then sequence.
+type delayHeap[V any] []delayItem[V]
+
+func (self delayHeap[V]) Len() int { return len(self) }
+
+func (self delayHeap[V]) Less(i, j int) bool {
+	if self[i].at.Equal(self[j].at) {
+		return self[i].seq < self[j].seq
+	}
+	return self[i].at.Before(self[j].at)
+}
+
+func (self delayHeap[V]) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+
+func (self *delayHeap[V]) Push(x any) { *self = append(*self, x.(delayItem[V])) }
+
+func (self *delayHeap[V]) Pop() (x any) {
+	var old = *self
+	var n = len(old) - 1
+	x = old[n]
+	old[n] = delayItem[V]{}
+	*self = old[:n]
+	return
+}
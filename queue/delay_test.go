@@ -0,0 +1,211 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced Clock.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock *fakeClock
+	at    time.Time
+	c     chan time.Time
+	done  bool
+}
+
+func newFakeClock() *fakeClock { return &fakeClock{now: time.Unix(1000, 0)} }
+
+func (self *fakeClock) Now() time.Time {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.now
+}
+
+func (self *fakeClock) NewTimer(d time.Duration) Timer {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	var t = &fakeTimer{clock: self, at: self.now.Add(d), c: make(chan time.Time, 1)}
+	self.timers = append(self.timers, t)
+	return t
+}
+
+func (self *fakeClock) Advance(d time.Duration) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.now = self.now.Add(d)
+	for _, t := range self.timers {
+		if !t.done && !t.at.After(self.now) {
+			t.done = true
+			t.c <- self.now
+		}
+	}
+}
+
+func (self *fakeClock) Waiters() (n int) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	for _, t := range self.timers {
+		if !t.done {
+			n++
+		}
+	}
+	return
+}
+
+func (self *fakeTimer) C() <-chan time.Time { return self.c }
+
+func (self *fakeTimer) Stop() (stopped bool) {
+	self.clock.mu.Lock()
+	defer self.clock.mu.Unlock()
+	stopped = !self.done
+	self.done = true
+	return
+}
+
+func TestDelayQueueTryPop(t *testing.T) {
+	var clock = newFakeClock()
+	var q = NewDelay[int](clock)
+
+	q.Push(2, 2*time.Second)
+	q.Push(1, time.Second)
+	q.PushAt(0, clock.Now())
+
+	if l := q.Len(); l != 3 {
+		t.Fatalf("expected len 3, got %d", l)
+	}
+	if v, ok := q.TryPop(); !ok || v != 0 {
+		t.Fatalf("expected (0, true), got (%d, %t)", v, ok)
+	}
+	if _, ok := q.TryPop(); ok {
+		t.Fatal("expected no due item")
+	}
+	clock.Advance(time.Second)
+	if v, ok := q.TryPop(); !ok || v != 1 {
+		t.Fatalf("expected (1, true), got (%d, %t)", v, ok)
+	}
+	if next, ok := q.Next(); !ok || !next.Equal(clock.Now().Add(time.Second)) {
+		t.Fatalf("unexpected next deadline %v", next)
+	}
+	clock.Advance(time.Second)
+	if v, ok := q.TryPop(); !ok || v != 2 {
+		t.Fatalf("expected (2, true), got (%d, %t)", v, ok)
+	}
+	if _, ok := q.Next(); ok {
+		t.Fatal("expected empty queue")
+	}
+}
+
+func TestDelayQueueFIFOOnEqualDeadline(t *testing.T) {
+	var clock = newFakeClock()
+	var q = NewDelay[int](clock)
+	var at = clock.Now()
+	for i := 0; i < 10; i++ {
+		q.PushAt(i, at)
+	}
+	for i := 0; i < 10; i++ {
+		if v, ok := q.TryPop(); !ok || v != i {
+			t.Fatalf("expected (%d, true), got (%d, %t)", i, v, ok)
+		}
+	}
+}
+
+func TestDelayQueuePopBlocks(t *testing.T) {
+	var clock = newFakeClock()
+	var q = NewDelay[string](clock)
+	q.Push("a", time.Minute)
+
+	var result = make(chan string)
+	go func() {
+		v, err := q.Pop(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		result <- v
+	}()
+
+	waitFor(t, func() bool { return clock.Waiters() == 1 })
+	select {
+	case v := <-result:
+		t.Fatalf("pop returned %q before deadline", v)
+	default:
+	}
+
+	clock.Advance(time.Minute)
+	if v := <-result; v != "a" {
+		t.Fatalf("expected a, got %q", v)
+	}
+}
+
+func TestDelayQueuePopWakesOnEarlierPush(t *testing.T) {
+	var clock = newFakeClock()
+	var q = NewDelay[string](clock)
+	q.Push("late", time.Hour)
+
+	var result = make(chan string)
+	go func() {
+		v, _ := q.Pop(context.Background())
+		result <- v
+	}()
+
+	waitFor(t, func() bool { return clock.Waiters() == 1 })
+	q.Push("now", 0)
+	if v := <-result; v != "now" {
+		t.Fatalf("expected now, got %q", v)
+	}
+}
+
+func TestDelayQueuePopContext(t *testing.T) {
+	var q = NewDelay[int](newFakeClock())
+	var ctx, cancel = context.WithCancel(context.Background())
+
+	var result = make(chan error)
+	go func() {
+		_, err := q.Pop(ctx)
+		result <- err
+	}()
+	cancel()
+	if err := <-result; err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestDelayQueueSystemClock(t *testing.T) {
+	var q = NewDelay[int](nil)
+	q.Push(1, 10*time.Millisecond)
+	var start = time.Now()
+	v, err := q.Pop(context.Background())
+	if err != nil || v != 1 {
+		t.Fatalf("expected (1, nil), got (%d, %v)", v, err)
+	}
+	if time.Since(start) < 10*time.Millisecond {
+		t.Fatal("pop returned before deadline")
+	}
+}
+
+// waitFor polls cond until it returns true or the test times out.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	var deadline = time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func BenchmarkDelayQueuePushTryPop(b *testing.B) {
+	var q = NewDelay[int](nil)
+	for i := 0; i < b.N; i++ {
+		q.Push(i, 0)
+		q.TryPop()
+	}
+}
@@ -0,0 +1,582 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrEmpty is returned by [DurableQueue.Pop] when there are no items to
+	// pop.
+	ErrEmpty = errors.New("queue empty")
+	// ErrClosed is returned when using a closed [DurableQueue].
+	ErrClosed = errors.New("queue closed")
+	// ErrInvalidOffset is returned when acknowledging an offset that was
+	// not popped.
+	ErrInvalidOffset = errors.New("invalid offset")
+	// ErrCorrupt is returned when a queue directory contains damaged data
+	// that can not be recovered by truncating an incomplete write.
+	ErrCorrupt = errors.New("corrupt queue data")
+)
+
+// Codec encodes and decodes items stored by a [DurableQueue].
+type Codec[V any] interface {
+	// Encode returns v encoded as bytes.
+	Encode(v V) ([]byte, error)
+	// Decode returns the item encoded in data.
+	Decode(data []byte) (V, error)
+}
+
+// JSONCodec is a [Codec] that uses encoding/json.
+type JSONCodec[V any] struct{}
+
+// Encode returns v encoded as JSON.
+func (JSONCodec[V]) Encode(v V) ([]byte, error) { return json.Marshal(v) }
+
+// Decode returns the item decoded from JSON data.
+func (JSONCodec[V]) Decode(data []byte) (v V, err error) {
+	err = json.Unmarshal(data, &v)
+	return
+}
+
+// DurableOptions configure a [DurableQueue].
+type DurableOptions[V any] struct {
+	// Codec encodes and decodes items.
+	// If nil, [JSONCodec] is used.
+	Codec Codec[V]
+	// SegmentSize is the size in bytes after which a new segment file is
+	// started. If <= 0, 16MiB is used.
+	SegmentSize int64
+	// Sync, if true, flushes segment and offset files to stable storage
+	// after every write. Without it data survives a process crash but may
+	// be lost on an operating system crash or power loss.
+	Sync bool
+}
+
+const (
+	// defaultSegmentSize is the default DurableOptions.SegmentSize.
+	defaultSegmentSize = 16 << 20
+	// segmentExt is the extension of segment files.
+	segmentExt = ".seg"
+	// ackFile is the name of the file holding the acknowledged offset.
+	ackFile = "consumer.ack"
+	// recordHeaderSize is the size of the length and checksum header that
+	// precedes each record payload.
+	recordHeaderSize = 8
+)
+
+// DurableQueue is a concurrency safe queue persisted to a directory.
+//
+// Pushed items are appended to segment files as length prefixed, checksummed
+// records and are assigned sequential offsets. Popped items remain on disk
+// until acknowledged with [DurableQueue.Ack]. When a queue is reopened after
+// a crash it truncates any incomplete trailing write and resumes popping
+// from the first unacknowledged item, so items are delivered at least once.
+// Segments that hold only acknowledged items are deleted.
+//
+// Example:
+//
+//	q, err := OpenDurable[string]("/var/lib/outbox", nil)
+//	if err != nil {
+//		return err
+//	}
+//	defer q.Close()
+//	q.Push("message")
+//	v, offset, err := q.Pop()
+//	// deliver v
+//	q.Ack(offset)
+type DurableQueue[V any] struct {
+	mu       sync.Mutex
+	dir      string
+	codec    Codec[V]
+	segSize  int64
+	sync     bool
+	segments []*segment
+	writer   segmentFile
+	reader   *os.File
+	readSeg  *segment
+	next     uint64
+	read     uint64
+	acked    uint64
+	closed   bool
+}
+
+// segmentFile is the segment file a [DurableQueue] appends records to.
+type segmentFile interface {
+	io.WriteCloser
+	Sync() error
+	Truncate(size int64) error
+}
+
+// segment describes a segment file of a [DurableQueue].
+type segment struct {
+	base  uint64
+	count uint64
+	size  int64
+	path  string
+}
+
+// OpenDurable opens the queue stored in dir, creating dir if it does not
+// exist. A nil opts uses default options.
+//
+// Example:
+//
+//	q, err := OpenDurable[int]("queue", &DurableOptions[int]{Sync: true})
+func OpenDurable[V any](dir string, opts *DurableOptions[V]) (out *DurableQueue[V], err error) {
+	if opts == nil {
+		opts = &DurableOptions[V]{}
+	}
+	out = &DurableQueue[V]{
+		dir:     dir,
+		codec:   opts.Codec,
+		segSize: opts.SegmentSize,
+		sync:    opts.Sync,
+	}
+	if out.codec == nil {
+		out.codec = JSONCodec[V]{}
+	}
+	if out.segSize <= 0 {
+		out.segSize = defaultSegmentSize
+	}
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	if err = out.recover(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Len returns the number of items pushed but not yet popped.
+func (self *DurableQueue[V]) Len() (l int) {
+	self.mu.Lock()
+	l = int(self.next - self.read)
+	self.mu.Unlock()
+	return
+}
+
+// Pending returns the number of items popped but not yet acknowledged.
+func (self *DurableQueue[V]) Pending() (l int) {
+	self.mu.Lock()
+	l = int(self.read - self.acked)
+	self.mu.Unlock()
+	return
+}
+
+// Push appends v to end of queue and returns its offset.
+//
+// Example:
+//
+//	offset, err := q.Push("message")
+func (self *DurableQueue[V]) Push(v V) (offset uint64, err error) {
+	data, err := self.codec.Encode(v)
+	if err != nil {
+		return 0, err
+	}
+	var rec = make([]byte, recordHeaderSize+len(data))
+	binary.LittleEndian.PutUint32(rec[0:4], uint32(len(data)))
+	binary.LittleEndian.PutUint32(rec[4:8], crc32.ChecksumIEEE(data))
+	copy(rec[recordHeaderSize:], data)
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.closed {
+		return 0, ErrClosed
+	}
+	var seg = self.segments[len(self.segments)-1]
+	if seg.count > 0 && seg.size >= self.segSize {
+		if seg, err = self.roll(); err != nil {
+			return 0, err
+		}
+	}
+	if _, err = self.writer.Write(rec); err != nil {
+		return 0, self.discard(seg, err)
+	}
+	if self.sync {
+		if err = self.writer.Sync(); err != nil {
+			return 0, self.discard(seg, err)
+		}
+	}
+	seg.size += int64(len(rec))
+	seg.count++
+	offset = self.next
+	self.next++
+	return
+}
+
+// discard drops a possibly written record after a failed write by truncating
+// seg back to its size and returns err. If seg can not be truncated the
+// record may remain and offsets of later pushes would not match the file so
+// the queue is closed; reopening it recovers the segment.
+func (self *DurableQueue[V]) discard(seg *segment, err error) error {
+	var terr = self.writer.Truncate(seg.size)
+	if terr == nil {
+		return err
+	}
+	self.closed = true
+	self.closeReader()
+	self.writer.Close()
+	return errors.Join(err, fmt.Errorf("truncate segment %s: %w", seg.path, terr))
+}
+
+// Pop returns the next item that was not yet popped and its offset. It
+// returns [ErrEmpty] if there are no such items.
+//
+// A popped item is delivered again after the queue is reopened or
+// [DurableQueue.Rewind] is called, unless its offset is acknowledged.
+// If the item can not be decoded Pop returns the codec error along with the
+// item offset, which is still considered popped and can be acknowledged to
+// skip the item.
+//
+// Example:
+//
+//	v, offset, err := q.Pop()
+//	if errors.Is(err, ErrEmpty) {
+//		// nothing to do
+//	}
+func (self *DurableQueue[V]) Pop() (v V, offset uint64, err error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.closed {
+		return v, 0, ErrClosed
+	}
+	if self.read >= self.next {
+		return v, 0, ErrEmpty
+	}
+	data, err := self.readNext()
+	if err != nil {
+		return v, 0, err
+	}
+	offset = self.read
+	self.read++
+	if v, err = self.codec.Decode(data); err != nil {
+		err = fmt.Errorf("decode item %d: %w", offset, err)
+	}
+	return
+}
+
+// Ack acknowledges all popped items up to and including offset. Segments
+// holding only acknowledged items are deleted. It returns
+// [ErrInvalidOffset] if offset was not popped.
+//
+// Example:
+//
+//	v, offset, _ := q.Pop()
+//	q.Ack(offset)
+func (self *DurableQueue[V]) Ack(offset uint64) (err error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.closed {
+		return ErrClosed
+	}
+	if offset >= self.read {
+		return ErrInvalidOffset
+	}
+	if offset < self.acked {
+		return nil
+	}
+	if err = self.writeAck(offset + 1); err != nil {
+		return
+	}
+	self.acked = offset + 1
+	return self.compact()
+}
+
+// Rewind makes all popped but unacknowledged items available to Pop again.
+func (self *DurableQueue[V]) Rewind() (err error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.closed {
+		return ErrClosed
+	}
+	self.read = self.acked
+	return self.closeReader()
+}
+
+// Close closes the queue files. Items not acknowledged are delivered again
+// when the queue is reopened.
+func (self *DurableQueue[V]) Close() (err error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.closed {
+		return ErrClosed
+	}
+	self.closed = true
+	err = self.closeReader()
+	if e := self.writer.Close(); err == nil {
+		err = e
+	}
+	return
+}
+
+// recover loads segments and the acknowledged offset from disk, truncating
+// an incomplete record at the end of the last segment.
+func (self *DurableQueue[V]) recover() (err error) {
+	entries, err := os.ReadDir(self.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		var name = entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		base, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		self.segments = append(self.segments, &segment{
+			base: base,
+			path: filepath.Join(self.dir, name),
+		})
+	}
+	slices.SortFunc(self.segments, func(a, b *segment) int {
+		switch {
+		case a.base < b.base:
+			return -1
+		case a.base > b.base:
+			return 1
+		}
+		return 0
+	})
+
+	for i, seg := range self.segments {
+		var last = i == len(self.segments)-1
+		if err = scanSegment(seg, last); err != nil {
+			return err
+		}
+		if !last && seg.base+seg.count != self.segments[i+1].base {
+			return fmt.Errorf("%w: gap after segment %s", ErrCorrupt, seg.path)
+		}
+	}
+
+	if self.acked, err = self.readAck(); err != nil {
+		return err
+	}
+	if len(self.segments) > 0 {
+		var first, last = self.segments[0], self.segments[len(self.segments)-1]
+		self.next = last.base + last.count
+		self.acked = max(self.acked, first.base)
+		// Acknowledged items lost to an incomplete write can not be
+		// delivered again anyway.
+		self.acked = min(self.acked, self.next)
+	} else {
+		self.next = self.acked
+		self.segments = append(self.segments, &segment{
+			base: self.next,
+			path: self.segmentPath(self.next),
+		})
+	}
+	self.read = self.acked
+
+	var seg = self.segments[len(self.segments)-1]
+	if self.writer, err = os.OpenFile(seg.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644); err != nil {
+		return err
+	}
+	return self.compact()
+}
+
+// scanSegment counts valid records in seg. If truncate is true an invalid
+// or incomplete trailing record is removed from the file, otherwise it is
+// reported as [ErrCorrupt].
+func scanSegment(seg *segment, truncate bool) (err error) {
+	file, err := os.Open(seg.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	for {
+		var n int
+		if n, err = readRecord(file, nil); err != nil {
+			break
+		}
+		seg.size += int64(n)
+		seg.count++
+	}
+	if err == io.EOF {
+		return nil
+	}
+	if !truncate {
+		return fmt.Errorf("%w: segment %s at %d: %v", ErrCorrupt, seg.path, seg.size, err)
+	}
+	return os.Truncate(seg.path, seg.size)
+}
+
+// readRecord reads a record from r and returns its size including the header.
+// If data is not nil the payload is stored to it. It returns io.EOF only if
+// r is at end of file before the record.
+func readRecord(r io.Reader, data *[]byte) (n int, err error) {
+	var header [recordHeaderSize]byte
+	if _, err = io.ReadFull(r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = errors.New("incomplete record header")
+		}
+		return
+	}
+	// Copy instead of allocating the declared size up front so that a
+	// damaged length does not cause a huge allocation.
+	var buf bytes.Buffer
+	if _, err = io.CopyN(&buf, r, int64(binary.LittleEndian.Uint32(header[0:4]))); err != nil {
+		return 0, errors.New("incomplete record")
+	}
+	var payload = buf.Bytes()
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(header[4:8]) {
+		return 0, errors.New("record checksum mismatch")
+	}
+	if data != nil {
+		*data = payload
+	}
+	return recordHeaderSize + len(payload), nil
+}
+
+// readNext reads the record at the read offset, opening segments as needed.
+func (self *DurableQueue[V]) readNext() (data []byte, err error) {
+	if self.reader == nil || self.read >= self.readSeg.base+self.readSeg.count {
+		if err = self.openReader(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err = readRecord(self.reader, &data); err != nil {
+		self.closeReader()
+		return nil, fmt.Errorf("%w: read item %d: %v", ErrCorrupt, self.read, err)
+	}
+	return
+}
+
+// openReader opens the segment containing the read offset and skips to it.
+func (self *DurableQueue[V]) openReader() (err error) {
+	if err = self.closeReader(); err != nil {
+		return
+	}
+	var i, found = slices.BinarySearchFunc(self.segments, self.read, func(seg *segment, offset uint64) int {
+		switch {
+		case offset < seg.base:
+			return 1
+		case offset >= seg.base+seg.count:
+			return -1
+		}
+		return 0
+	})
+	if !found {
+		return fmt.Errorf("%w: no segment holds item %d", ErrCorrupt, self.read)
+	}
+	var seg = self.segments[i]
+	if self.reader, err = os.Open(seg.path); err != nil {
+		return
+	}
+	self.readSeg = seg
+	for skip := self.read - seg.base; skip > 0; skip-- {
+		if _, err = readRecord(self.reader, nil); err != nil {
+			self.closeReader()
+			return fmt.Errorf("%w: segment %s: %v", ErrCorrupt, seg.path, err)
+		}
+	}
+	return nil
+}
+
+// closeReader closes the current read segment, if any.
+func (self *DurableQueue[V]) closeReader() (err error) {
+	if self.reader != nil {
+		err = self.reader.Close()
+		self.reader, self.readSeg = nil, nil
+	}
+	return
+}
+
+// roll starts a new segment at the next offset and returns it.
+func (self *DurableQueue[V]) roll() (seg *segment, err error) {
+	seg = &segment{base: self.next, path: self.segmentPath(self.next)}
+	file, err := os.OpenFile(seg.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err = self.writer.Close(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	self.writer = file
+	self.segments = append(self.segments, seg)
+	return seg, nil
+}
+
+// compact deletes segments before the last one that hold only acknowledged
+// items.
+func (self *DurableQueue[V]) compact() (err error) {
+	var n int
+	for n < len(self.segments)-1 {
+		var seg = self.segments[n]
+		if seg.base+seg.count > self.acked {
+			break
+		}
+		if seg == self.readSeg {
+			self.closeReader()
+		}
+		if err = os.Remove(seg.path); err != nil && !os.IsNotExist(err) {
+			break
+		}
+		err = nil
+		n++
+	}
+	self.segments = slices.Delete(self.segments, 0, n)
+	return
+}
+
+// readAck returns the acknowledged offset stored on disk or 0 if there is
+// none.
+func (self *DurableQueue[V]) readAck() (acked uint64, err error) {
+	data, err := os.ReadFile(filepath.Join(self.dir, ackFile))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != 12 || crc32.ChecksumIEEE(data[:8]) != binary.LittleEndian.Uint32(data[8:]) {
+		return 0, fmt.Errorf("%w: invalid %s", ErrCorrupt, ackFile)
+	}
+	return binary.LittleEndian.Uint64(data[:8]), nil
+}
+
+// writeAck atomically replaces the acknowledged offset stored on disk.
+func (self *DurableQueue[V]) writeAck(acked uint64) (err error) {
+	var data [12]byte
+	binary.LittleEndian.PutUint64(data[:8], acked)
+	binary.LittleEndian.PutUint32(data[8:], crc32.ChecksumIEEE(data[:8]))
+
+	var path = filepath.Join(self.dir, ackFile)
+	file, err := os.Create(path + ".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(data[:]); err == nil && self.sync {
+		err = file.Sync()
+	}
+	if e := file.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// segmentPath returns the path of a segment starting at base.
+func (self *DurableQueue[V]) segmentPath(base uint64) string {
+	return filepath.Join(self.dir, fmt.Sprintf("%020d%s", base, segmentExt))
+}
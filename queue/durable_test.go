@@ -0,0 +1,281 @@
+package queue
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// stringCodec stores strings as raw bytes.
+type stringCodec struct{}
+
+func (stringCodec) Encode(v string) ([]byte, error) { return []byte(v), nil }
+
+func (stringCodec) Decode(data []byte) (string, error) { return string(data), nil }
+
+func openTestDurable(t *testing.T, dir string, segSize int64) *DurableQueue[string] {
+	t.Helper()
+	q, err := OpenDurable(dir, &DurableOptions[string]{Codec: stringCodec{}, SegmentSize: segSize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func expectPop(t *testing.T, q *DurableQueue[string], want string, wantOffset uint64) {
+	t.Helper()
+	v, offset, err := q.Pop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != want || offset != wantOffset {
+		t.Fatalf("expected (%q, %d), got (%q, %d)", want, wantOffset, v, offset)
+	}
+}
+
+func segmentCount(t *testing.T, dir string) int {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(matches)
+}
+
+func TestDurableQueue(t *testing.T) {
+	var dir = t.TempDir()
+	q := openTestDurable(t, dir, 0)
+	if _, _, err := q.Pop(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		offset, err := q.Push(strconv.Itoa(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if offset != uint64(i) {
+			t.Fatalf("expected offset %d, got %d", i, offset)
+		}
+	}
+	if l := q.Len(); l != 3 {
+		t.Fatalf("expected len 3, got %d", l)
+	}
+	expectPop(t, q, "0", 0)
+	expectPop(t, q, "1", 1)
+	if p := q.Pending(); p != 2 {
+		t.Fatalf("expected 2 pending, got %d", p)
+	}
+	if err := q.Ack(2); !errors.Is(err, ErrInvalidOffset) {
+		t.Fatalf("expected ErrInvalidOffset, got %v", err)
+	}
+	if err := q.Ack(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Rewind(); err != nil {
+		t.Fatal(err)
+	}
+	expectPop(t, q, "1", 1)
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Push("x"); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+
+	// Unacknowledged items are delivered again after reopening.
+	q = openTestDurable(t, dir, 0)
+	defer q.Close()
+	if l := q.Len(); l != 2 {
+		t.Fatalf("expected len 2, got %d", l)
+	}
+	expectPop(t, q, "1", 1)
+	expectPop(t, q, "2", 2)
+	if _, err := q.Push("3"); err != nil {
+		t.Fatal(err)
+	}
+	expectPop(t, q, "3", 3)
+}
+
+func TestDurableQueueSegments(t *testing.T) {
+	var dir = t.TempDir()
+	// Every record is larger than the segment size so each gets its own.
+	q := openTestDurable(t, dir, 1)
+	for i := 0; i < 5; i++ {
+		if _, err := q.Push(strconv.Itoa(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := segmentCount(t, dir); n != 5 {
+		t.Fatalf("expected 5 segments, got %d", n)
+	}
+	for i := 0; i < 3; i++ {
+		expectPop(t, q, strconv.Itoa(i), uint64(i))
+	}
+	if err := q.Ack(2); err != nil {
+		t.Fatal(err)
+	}
+	if n := segmentCount(t, dir); n != 2 {
+		t.Fatalf("expected 2 segments after compaction, got %d", n)
+	}
+	expectPop(t, q, "3", 3)
+	q.Close()
+
+	q = openTestDurable(t, dir, 1)
+	defer q.Close()
+	expectPop(t, q, "3", 3)
+	expectPop(t, q, "4", 4)
+	if err := q.Ack(4); err != nil {
+		t.Fatal(err)
+	}
+	// The active segment is always kept.
+	if n := segmentCount(t, dir); n != 1 {
+		t.Fatalf("expected 1 segment, got %d", n)
+	}
+	if offset, err := q.Push("5"); err != nil || offset != 5 {
+		t.Fatalf("expected (5, nil), got (%d, %v)", offset, err)
+	}
+}
+
+func TestDurableQueueRecoverTornWrite(t *testing.T) {
+	var dir = t.TempDir()
+	q := openTestDurable(t, dir, 0)
+	q.Push("a")
+	q.Push("b")
+	q.Close()
+
+	// Simulate a crash in the middle of writing a record.
+	var path = filepath.Join(dir, "00000000000000000000"+segmentExt)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{10, 0, 0, 0, 1, 2})
+	f.Close()
+
+	q = openTestDurable(t, dir, 0)
+	defer q.Close()
+	if l := q.Len(); l != 2 {
+		t.Fatalf("expected len 2, got %d", l)
+	}
+	if offset, err := q.Push("c"); err != nil || offset != 2 {
+		t.Fatalf("expected (2, nil), got (%d, %v)", offset, err)
+	}
+	expectPop(t, q, "a", 0)
+	expectPop(t, q, "b", 1)
+	expectPop(t, q, "c", 2)
+}
+
+// failingFile is a segmentFile whose Sync, and optionally Truncate, fail.
+type failingFile struct {
+	segmentFile
+	truncate bool
+}
+
+var errInjected = errors.New("injected")
+
+func (self failingFile) Sync() error { return errInjected }
+
+func (self failingFile) Truncate(size int64) error {
+	if self.truncate {
+		return errInjected
+	}
+	return self.segmentFile.Truncate(size)
+}
+
+func TestDurableQueueSyncError(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		opts = &DurableOptions[string]{Codec: stringCodec{}, Sync: true}
+	)
+	q, err := OpenDurable(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Push("a")
+	var file = q.writer
+	q.writer = failingFile{segmentFile: file}
+	if _, err := q.Push("b"); !errors.Is(err, errInjected) {
+		t.Fatalf("expected injected error, got %v", err)
+	}
+	q.writer = file
+	if offset, err := q.Push("c"); err != nil || offset != 1 {
+		t.Fatalf("expected (1, nil), got (%d, %v)", offset, err)
+	}
+	expectPop(t, q, "a", 0)
+	expectPop(t, q, "c", 1)
+	q.Close()
+
+	// The failed record was removed from the segment.
+	if q, err = OpenDurable(dir, opts); err != nil {
+		t.Fatal(err)
+	}
+	if l := q.Len(); l != 2 {
+		t.Fatalf("expected len 2, got %d", l)
+	}
+	expectPop(t, q, "a", 0)
+	expectPop(t, q, "c", 1)
+
+	// A record that can not be removed closes the queue.
+	q.writer = failingFile{segmentFile: q.writer, truncate: true}
+	if _, err := q.Push("d"); !errors.Is(err, errInjected) {
+		t.Fatalf("expected injected error, got %v", err)
+	}
+	if _, err := q.Push("e"); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	q = openTestDurable(t, dir, 0)
+	defer q.Close()
+	expectPop(t, q, "a", 0)
+	expectPop(t, q, "c", 1)
+	expectPop(t, q, "d", 2)
+}
+
+func TestDurableQueueCorruptSegment(t *testing.T) {
+	var dir = t.TempDir()
+	q := openTestDurable(t, dir, 1)
+	q.Push("a")
+	q.Push("b")
+	q.Close()
+
+	// Damage a segment that is not the last one.
+	var path = filepath.Join(dir, "00000000000000000000"+segmentExt)
+	if err := os.WriteFile(path, []byte{1, 0, 0, 0, 0, 0, 0, 0, 'a'}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenDurable[string](dir, nil); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("expected ErrCorrupt, got %v", err)
+	}
+}
+
+func TestDurableQueueJSONCodec(t *testing.T) {
+	type item struct {
+		ID   int
+		Name string
+	}
+	q, err := OpenDurable[item](t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+	q.Push(item{1, "one"})
+	v, _, err := q.Pop()
+	if err != nil || v != (item{1, "one"}) {
+		t.Fatalf("expected ({1 one}, nil), got (%v, %v)", v, err)
+	}
+}
+
+func BenchmarkDurableQueuePushPop(b *testing.B) {
+	q, err := OpenDurable(b.TempDir(), &DurableOptions[string]{Codec: stringCodec{}})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer q.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Push("item")
+		_, offset, _ := q.Pop()
+		q.Ack(offset)
+	}
+}
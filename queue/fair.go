@@ -0,0 +1,272 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"container/list"
+	"sync"
+)
+
+// FairQueue is a queue of per-key sub-queues that are served fairly so that
+// one busy key can not starve the others.
+//
+// Pop serves keys with queued items using deficit round-robin: each time a
+// key gets its turn it may pop as many items as its weight before the turn
+// passes to the next key. All keys have a weight of 1 by default, which makes
+// Pop a plain round-robin over keys. Items of the same key are popped in the
+// order they were pushed.
+//
+// Example:
+//
+//	q := NewFair[string, int](0)
+//	q.Push("a", 1)
+//	q.Push("a", 2)
+//	q.Push("b", 3)
+//	_, v, _ := q.Pop() // v == 1
+//	_, v, _ = q.Pop()  // v == 3
+//	_, v, _ = q.Pop()  // v == 2
+type FairQueue[K comparable, V any] struct {
+	queues  map[K]*fairSub[V]
+	weights map[K]int
+	// active holds keys with queued items in service order.
+	active *list.List
+	// current is the element of the key whose turn it is or nil to start
+	// from the front of active.
+	current *list.Element
+	limit   int
+	len     int
+}
+
+// fairSub is the sub-queue of a key in a [FairQueue].
+type fairSub[V any] struct {
+	q       *Queue[V]
+	elem    *list.Element
+	deficit int
+}
+
+// NewFair returns a new [FairQueue] of V keyed by K that holds at most limit
+// items per key. A limit <= 0 means unlimited.
+//
+// Example:
+//
+//	q := NewFair[string, int](100)
+func NewFair[K comparable, V any](limit int) *FairQueue[K, V] {
+	return &FairQueue[K, V]{
+		queues:  make(map[K]*fairSub[V]),
+		weights: make(map[K]int),
+		active:  list.New(),
+		limit:   limit,
+	}
+}
+
+// Len returns the total number of items in the queue.
+func (self *FairQueue[K, V]) Len() int { return self.len }
+
+// KeyLen returns the number of items queued under key.
+//
+// Example:
+//
+//	q := NewFair[string, int](0)
+//	q.Push("a", 1)
+//	n := q.KeyLen("a") // n == 1
+func (self *FairQueue[K, V]) KeyLen(key K) int {
+	if sub, exists := self.queues[key]; exists {
+		return sub.q.Len()
+	}
+	return 0
+}
+
+// Backlog returns the number of queued items of every key that has any.
+//
+// Example:
+//
+//	q := NewFair[string, int](0)
+//	q.Push("a", 1)
+//	q.Push("a", 2)
+//	q.Push("b", 3)
+//	b := q.Backlog() // b == map[string]int{"a": 2, "b": 1}
+func (self *FairQueue[K, V]) Backlog() (out map[K]int) {
+	out = make(map[K]int, len(self.queues))
+	for key, sub := range self.queues {
+		out[key] = sub.q.Len()
+	}
+	return
+}
+
+// SetWeight sets the number of items key may pop per turn. A weight < 1 is
+// treated as 1. The weight is kept after the key runs out of items.
+//
+// Example:
+//
+//	q := NewFair[string, int](0)
+//	q.SetWeight("premium", 3)
+func (self *FairQueue[K, V]) SetWeight(key K, weight int) {
+	if weight <= 1 {
+		delete(self.weights, key)
+		return
+	}
+	self.weights[key] = weight
+}
+
+// Weight returns the weight of key.
+func (self *FairQueue[K, V]) Weight(key K) int {
+	if weight, exists := self.weights[key]; exists {
+		return weight
+	}
+	return 1
+}
+
+// Push pushes v to end of the sub-queue of key. It returns [ErrFull] if key
+// already holds the maximum number of items.
+//
+// Example:
+//
+//	q := NewFair[string, int](1)
+//	err := q.Push("a", 1) // err == nil
+//	err = q.Push("a", 2)  // err == ErrFull
+//	err = q.Push("b", 3)  // err == nil
+func (self *FairQueue[K, V]) Push(key K, v V) (err error) {
+	var sub, exists = self.queues[key]
+	if !exists {
+		sub = &fairSub[V]{q: New[V]()}
+		// A key that becomes active waits for a full round.
+		if self.current == nil {
+			sub.elem = self.active.PushBack(key)
+		} else {
+			sub.elem = self.active.InsertBefore(key, self.current)
+		}
+		self.queues[key] = sub
+	} else if self.limit > 0 && sub.q.Len() >= self.limit {
+		return ErrFull
+	}
+	sub.q.Push(v)
+	self.len++
+	return nil
+}
+
+// Pop returns the next item by fair order, the key it was queued under and
+// truth if one was found. Returned key and value should be ignored if truth
+// is false, they are zero values.
+//
+// Example:
+//
+//	q := NewFair[string, int](0)
+//	q.Push("a", 1)
+//	key, v, ok := q.Pop() // key == "a", v == 1, ok == true
+func (self *FairQueue[K, V]) Pop() (key K, v V, ok bool) {
+	var e = self.current
+	if e == nil {
+		if e = self.active.Front(); e == nil {
+			return
+		}
+	}
+	key = e.Value.(K)
+	var sub = self.queues[key]
+	if sub.deficit == 0 {
+		sub.deficit = self.Weight(key)
+	}
+	v, ok = sub.q.Pop()
+	sub.deficit--
+	self.len--
+
+	var next = e.Next()
+	if next == nil {
+		next = self.active.Front()
+	}
+	switch {
+	case sub.q.Len() == 0:
+		if next == e {
+			next = nil
+		}
+		self.active.Remove(e)
+		delete(self.queues, key)
+		self.current = next
+	case sub.deficit == 0:
+		self.current = next
+	default:
+		self.current = e
+	}
+	return
+}
+
+// SyncFairQueue is the concurrency safe version of [FairQueue].
+//
+// Example:
+//
+//	q := NewSyncFair[string, int](0)
+//	go q.Push("a", 1)
+//	key, v, ok := q.Pop()
+type SyncFairQueue[K comparable, V any] struct {
+	mu sync.Mutex
+	q  *FairQueue[K, V]
+}
+
+// NewSyncFair returns a new [SyncFairQueue] of V keyed by K that holds at
+// most limit items per key. A limit <= 0 means unlimited.
+//
+// Example:
+//
+//	q := NewSyncFair[string, int](100)
+func NewSyncFair[K comparable, V any](limit int) *SyncFairQueue[K, V] {
+	return &SyncFairQueue[K, V]{q: NewFair[K, V](limit)}
+}
+
+// Len returns the total number of items in the queue.
+func (self *SyncFairQueue[K, V]) Len() (l int) {
+	self.mu.Lock()
+	l = self.q.Len()
+	self.mu.Unlock()
+	return
+}
+
+// KeyLen returns the number of items queued under key.
+func (self *SyncFairQueue[K, V]) KeyLen(key K) (l int) {
+	self.mu.Lock()
+	l = self.q.KeyLen(key)
+	self.mu.Unlock()
+	return
+}
+
+// Backlog returns the number of queued items of every key that has any.
+func (self *SyncFairQueue[K, V]) Backlog() (out map[K]int) {
+	self.mu.Lock()
+	out = self.q.Backlog()
+	self.mu.Unlock()
+	return
+}
+
+// SetWeight sets the number of items key may pop per turn. A weight < 1 is
+// treated as 1.
+func (self *SyncFairQueue[K, V]) SetWeight(key K, weight int) {
+	self.mu.Lock()
+	self.q.SetWeight(key, weight)
+	self.mu.Unlock()
+}
+
+// Weight returns the weight of key.
+func (self *SyncFairQueue[K, V]) Weight(key K) (weight int) {
+	self.mu.Lock()
+	weight = self.q.Weight(key)
+	self.mu.Unlock()
+	return
+}
+
+// Push pushes v to end of the sub-queue of key. It returns [ErrFull] if key
+// already holds the maximum number of items.
+func (self *SyncFairQueue[K, V]) Push(key K, v V) (err error) {
+	self.mu.Lock()
+	err = self.q.Push(key, v)
+	self.mu.Unlock()
+	return
+}
+
+// Pop returns the next item by fair order, the key it was queued under and
+// truth if one was found.
+func (self *SyncFairQueue[K, V]) Pop() (key K, v V, ok bool) {
+	self.mu.Lock()
+	key, v, ok = self.q.Pop()
+	self.mu.Unlock()
+	return
+}
@@ -0,0 +1,160 @@
+package queue
+
+import (
+	"maps"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// popKeys pops all items from q and returns the keys they were queued under.
+func popKeys(q *FairQueue[string, int]) string {
+	var b strings.Builder
+	for {
+		key, _, ok := q.Pop()
+		if !ok {
+			return b.String()
+		}
+		b.WriteString(key)
+	}
+}
+
+func TestFairQueueRoundRobin(t *testing.T) {
+	q := NewFair[string, int](0)
+	for i := 0; i < 4; i++ {
+		q.Push("a", i)
+	}
+	q.Push("b", 0)
+	q.Push("c", 0)
+	q.Push("c", 1)
+	if l := q.Len(); l != 7 {
+		t.Fatalf("expected len 7, got %d", l)
+	}
+	if keys := popKeys(q); keys != "abcacaa" {
+		t.Fatalf("expected abcacaa, got %s", keys)
+	}
+	if l := q.Len(); l != 0 {
+		t.Fatalf("expected len 0, got %d", l)
+	}
+}
+
+func TestFairQueueOrderWithinKey(t *testing.T) {
+	q := NewFair[string, int](0)
+	for i := 0; i < 5; i++ {
+		q.Push("a", i)
+		q.Push("b", i+10)
+	}
+	var next = map[string]int{"a": 0, "b": 10}
+	for {
+		key, v, ok := q.Pop()
+		if !ok {
+			break
+		}
+		if v != next[key] {
+			t.Fatalf("key %s: expected %d, got %d", key, next[key], v)
+		}
+		next[key]++
+	}
+}
+
+func TestFairQueueWeighted(t *testing.T) {
+	q := NewFair[string, int](0)
+	q.SetWeight("a", 3)
+	if w := q.Weight("a"); w != 3 {
+		t.Fatalf("expected weight 3, got %d", w)
+	}
+	if w := q.Weight("b"); w != 1 {
+		t.Fatalf("expected weight 1, got %d", w)
+	}
+	for i := 0; i < 6; i++ {
+		q.Push("a", i)
+		q.Push("b", i)
+	}
+	if keys := popKeys(q); keys != "aaabaaabbbbb" {
+		t.Fatalf("expected aaabaaabbbbb, got %s", keys)
+	}
+}
+
+func TestFairQueueNewKeyWaitsForRound(t *testing.T) {
+	q := NewFair[string, int](0)
+	q.Push("a", 0)
+	q.Push("a", 1)
+	q.Push("b", 0)
+	q.Push("b", 1)
+	if key, _, _ := q.Pop(); key != "a" {
+		t.Fatalf("expected a, got %s", key)
+	}
+	// c joins while it is b's turn and is served after a.
+	q.Push("c", 0)
+	if keys := popKeys(q); keys != "bacb" {
+		t.Fatalf("expected bacb, got %s", keys)
+	}
+}
+
+func TestFairQueueLimitAndBacklog(t *testing.T) {
+	q := NewFair[string, int](2)
+	if err := q.Push("a", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Push("a", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Push("a", 3); err != ErrFull {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+	if err := q.Push("b", 1); err != nil {
+		t.Fatal(err)
+	}
+	if n := q.KeyLen("a"); n != 2 {
+		t.Fatalf("expected 2, got %d", n)
+	}
+	if n := q.KeyLen("c"); n != 0 {
+		t.Fatalf("expected 0, got %d", n)
+	}
+	if b := q.Backlog(); !maps.Equal(b, map[string]int{"a": 2, "b": 1}) {
+		t.Fatalf("unexpected backlog %v", b)
+	}
+}
+
+func TestSyncFairQueue(t *testing.T) {
+	q := NewSyncFair[int, int](0)
+	var wg sync.WaitGroup
+	for key := 0; key < 10; key++ {
+		wg.Add(1)
+		go func(key int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				q.Push(key, i)
+			}
+		}(key)
+	}
+	wg.Wait()
+	if l := q.Len(); l != 1000 {
+		t.Fatalf("expected len 1000, got %d", l)
+	}
+	// Each round serves every key once.
+	var counts = make(map[int]int)
+	for i := 0; i < 100; i++ {
+		key, _, ok := q.Pop()
+		if !ok {
+			t.Fatal("unexpected empty queue")
+		}
+		counts[key]++
+	}
+	for key, n := range counts {
+		if n != 10 {
+			t.Fatalf("key %d: expected 10 pops, got %d", key, n)
+		}
+	}
+}
+
+func BenchmarkFairQueuePushPop(b *testing.B) {
+	q := NewFair[int, int](0)
+	for i := 0; i < b.N; i++ {
+		q.Push(i%16, i)
+		if i%2 == 1 {
+			q.Pop()
+			q.Pop()
+		}
+	}
+}
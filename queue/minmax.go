@@ -0,0 +1,93 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package queue
+
+// MinMaxQueue is a [Queue] that reports its minimum and maximum items in
+// amortized O(1), suitable for sliding window computations. Items are
+// ordered by a compare function that returns a negative number if a < b, a
+// positive number if a > b and zero if they are equal, such as cmp.Compare.
+//
+// It keeps two monotonic deques alongside the queue: one of items that may
+// still become the minimum, in ascending order, and one of items that may
+// still become the maximum, in descending order.
+//
+// Example:
+//
+//	q := NewMinMax(cmp.Compare[int])
+//	q.Push(2)
+//	q.Push(1)
+//	q.Push(3)
+//	q.Pop()
+//	min, _ := q.Min() // min == 1
+//	max, _ := q.Max() // max == 3
+type MinMaxQueue[V any] struct {
+	q    *Queue[V]
+	cmp  func(a, b V) int
+	mins []V
+	maxs []V
+}
+
+// NewMinMax returns a new [MinMaxQueue] ordered by cmp.
+func NewMinMax[V any](cmp func(a, b V) int) *MinMaxQueue[V] {
+	return &MinMaxQueue[V]{q: New[V](), cmp: cmp}
+}
+
+// Len returns the number of items in the queue.
+func (self *MinMaxQueue[V]) Len() int { return self.q.Len() }
+
+// Push pushes v to end of queue.
+func (self *MinMaxQueue[V]) Push(v V) {
+	self.q.Push(v)
+	// Items smaller than v can never be the maximum while v is queued and
+	// vice versa. Equal items are kept so that each Pop removes its own.
+	for len(self.maxs) > 0 && self.cmp(self.maxs[len(self.maxs)-1], v) < 0 {
+		self.maxs = self.maxs[:len(self.maxs)-1]
+	}
+	self.maxs = append(self.maxs, v)
+	for len(self.mins) > 0 && self.cmp(self.mins[len(self.mins)-1], v) > 0 {
+		self.mins = self.mins[:len(self.mins)-1]
+	}
+	self.mins = append(self.mins, v)
+}
+
+// Pop returns item from the start of the queue and truth if one was found.
+// Returned value should be ignored if truth if false, it is the zero value of
+// Queue generic type.
+func (self *MinMaxQueue[V]) Pop() (v V, b bool) {
+	if v, b = self.q.Pop(); !b {
+		return
+	}
+	if self.cmp(self.maxs[0], v) == 0 {
+		self.maxs = popFront(self.maxs)
+	}
+	if self.cmp(self.mins[0], v) == 0 {
+		self.mins = popFront(self.mins)
+	}
+	return
+}
+
+// Min returns the smallest item in the queue and truth if the queue was not
+// empty.
+func (self *MinMaxQueue[V]) Min() (v V, b bool) {
+	if b = len(self.mins) > 0; b {
+		v = self.mins[0]
+	}
+	return
+}
+
+// Max returns the largest item in the queue and truth if the queue was not
+// empty.
+func (self *MinMaxQueue[V]) Max() (v V, b bool) {
+	if b = len(self.maxs) > 0; b {
+		v = self.maxs[0]
+	}
+	return
+}
+
+// popFront removes the first item of s, clearing it for the collector.
+func popFront[V any](s []V) []V {
+	s[0] = *new(V)
+	return s[1:]
+}
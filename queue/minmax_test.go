@@ -0,0 +1,59 @@
+package queue
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestMinMaxQueue(t *testing.T) {
+	q := NewMinMax(cmp.Compare[int])
+	if _, ok := q.Min(); ok {
+		t.Fatal("expected empty queue")
+	}
+	if _, ok := q.Max(); ok {
+		t.Fatal("expected empty queue")
+	}
+
+	var items []int
+	for i := 0; i < 1000; i++ {
+		if rand.Intn(3) == 0 && len(items) > 0 {
+			v, _ := q.Pop()
+			if v != items[0] {
+				t.Fatalf("expected pop %d, got %d", items[0], v)
+			}
+			items = items[1:]
+		} else {
+			var v = rand.Intn(20)
+			q.Push(v)
+			items = append(items, v)
+		}
+		if q.Len() != len(items) {
+			t.Fatalf("expected len %d, got %d", len(items), q.Len())
+		}
+		if len(items) == 0 {
+			if _, ok := q.Min(); ok {
+				t.Fatal("expected empty queue")
+			}
+			continue
+		}
+		if v, _ := q.Min(); v != slices.Min(items) {
+			t.Fatalf("expected min %d, got %d", slices.Min(items), v)
+		}
+		if v, _ := q.Max(); v != slices.Max(items) {
+			t.Fatalf("expected max %d, got %d", slices.Max(items), v)
+		}
+	}
+}
+
+func BenchmarkMinMaxQueueWindow(b *testing.B) {
+	q := NewMinMax(cmp.Compare[int])
+	for i := 0; i < b.N; i++ {
+		q.Push(rand.Int())
+		if q.Len() > 64 {
+			q.Pop()
+		}
+		q.Max()
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package queue
+
+import "sync/atomic"
+
+// cacheLinePad separates fields written by different goroutines so they do
+// not share a cache line.
+type cacheLinePad [64]byte
+
+// MPMCQueue is a bounded lock-free multi-producer multi-consumer queue.
+//
+// It is a ring of cells each tagged with a sequence number, as described by
+// Dmitry Vyukov. Producers and consumers claim positions with a compare and
+// swap and never block each other on a lock. Unlike [SyncQueue] it has a
+// fixed capacity, so Push reports whether the item was queued.
+//
+// Example:
+//
+//	q := NewMPMC[int](1024)
+//	go q.Push(1)
+//	v, ok := q.Pop()
+type MPMCQueue[V any] struct {
+	_     cacheLinePad
+	head  atomic.Uint64
+	_     cacheLinePad
+	tail  atomic.Uint64
+	_     cacheLinePad
+	mask  uint64
+	cells []mpmcCell[V]
+}
+
+// mpmcCell is a single slot of the [MPMCQueue] ring.
+type mpmcCell[V any] struct {
+	seq   atomic.Uint64
+	value V
+}
+
+// NewMPMC returns a new [MPMCQueue] of V that holds at least capacity items.
+// Capacity is rounded up to the next power of two, minimum 2.
+//
+// Example:
+//
+//	q := NewMPMC[int](1000) // q.Cap() == 1024
+func NewMPMC[V any](capacity int) *MPMCQueue[V] {
+	var size = 2
+	for size < capacity {
+		size <<= 1
+	}
+	var out = &MPMCQueue[V]{
+		mask:  uint64(size - 1),
+		cells: make([]mpmcCell[V], size),
+	}
+	for i := range out.cells {
+		out.cells[i].seq.Store(uint64(i))
+	}
+	return out
+}
+
+// Cap returns the maximum number of items the queue holds.
+func (self *MPMCQueue[V]) Cap() int { return len(self.cells) }
+
+// Len returns the approximate number of items in the queue. The value may
+// be stale by the time it is returned if the queue is used concurrently.
+func (self *MPMCQueue[V]) Len() int {
+	for {
+		var tail = self.tail.Load()
+		var head = self.head.Load()
+		if self.tail.Load() == tail {
+			if n := int(tail - head); n > 0 {
+				return n
+			}
+			return 0
+		}
+	}
+}
+
+// Push pushes v to end of queue and returns truth if it was queued or false
+// if the queue is full.
+//
+// Example:
+//
+//	q := NewMPMC[int](2)
+//	ok := q.Push(1) // ok == true
+//	ok = q.Push(2)  // ok == true
+//	ok = q.Push(3)  // ok == false
+func (self *MPMCQueue[V]) Push(v V) (ok bool) {
+	var pos = self.tail.Load()
+	for {
+		var cell = &self.cells[pos&self.mask]
+		switch diff := int64(cell.seq.Load()) - int64(pos); {
+		case diff == 0:
+			if self.tail.CompareAndSwap(pos, pos+1) {
+				cell.value = v
+				cell.seq.Store(pos + 1)
+				return true
+			}
+		case diff < 0:
+			return false
+		}
+		// Another producer claimed pos, retry with the new tail.
+		pos = self.tail.Load()
+	}
+}
+
+// Pop returns item from the start of the queue and truth if one was found.
+// Returned value should be ignored if truth if false, it is the zero value of
+// Queue generic type.
+//
+// Example:
+//
+//	q := NewMPMC[int](2)
+//	q.Push(1)
+//	v, ok := q.Pop() // v == 1, ok == true
+//	v, ok = q.Pop()  // v == 0, ok == false
+func (self *MPMCQueue[V]) Pop() (v V, ok bool) {
+	var pos = self.head.Load()
+	for {
+		var cell = &self.cells[pos&self.mask]
+		switch diff := int64(cell.seq.Load()) - int64(pos+1); {
+		case diff == 0:
+			if self.head.CompareAndSwap(pos, pos+1) {
+				v = cell.value
+				cell.value = *new(V)
+				cell.seq.Store(pos + self.mask + 1)
+				return v, true
+			}
+		case diff < 0:
+			return v, false
+		}
+		// Another consumer claimed pos, retry with the new head.
+		pos = self.head.Load()
+	}
+}
@@ -0,0 +1,132 @@
+package queue
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestMPMCQueue(t *testing.T) {
+	q := NewMPMC[int](3)
+	if c := q.Cap(); c != 4 {
+		t.Fatalf("expected cap 4, got %d", c)
+	}
+	if _, ok := q.Pop(); ok {
+		t.Fatal("expected empty queue")
+	}
+	for i := 0; i < 4; i++ {
+		if !q.Push(i) {
+			t.Fatalf("push %d failed", i)
+		}
+	}
+	if q.Push(4) {
+		t.Fatal("expected full queue")
+	}
+	if l := q.Len(); l != 4 {
+		t.Fatalf("expected len 4, got %d", l)
+	}
+	// Wrap around the ring a few times.
+	for i := 0; i < 20; i++ {
+		if v, ok := q.Pop(); !ok || v != i {
+			t.Fatalf("expected (%d, true), got (%d, %t)", i, v, ok)
+		}
+		if !q.Push(i + 4) {
+			t.Fatalf("push %d failed", i+4)
+		}
+	}
+}
+
+func TestMPMCQueueConcurrent(t *testing.T) {
+	const (
+		producers = 8
+		consumers = 8
+		perProd   = 2000
+	)
+	q := NewMPMC[int](64)
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProd; i++ {
+				for !q.Push(p*perProd + i) {
+					runtime.Gosched()
+				}
+			}
+		}(p)
+	}
+
+	var (
+		mu   sync.Mutex
+		seen = make([]bool, producers*perProd)
+		got  int
+		cwg  sync.WaitGroup
+	)
+	for c := 0; c < consumers; c++ {
+		cwg.Add(1)
+		go func() {
+			defer cwg.Done()
+			// Per producer values must arrive in push order.
+			var last = make([]int, producers)
+			for i := range last {
+				last[i] = -1
+			}
+			for {
+				mu.Lock()
+				if got == len(seen) {
+					mu.Unlock()
+					return
+				}
+				mu.Unlock()
+				v, ok := q.Pop()
+				if !ok {
+					runtime.Gosched()
+					continue
+				}
+				if p, i := v/perProd, v%perProd; i <= last[p] {
+					t.Errorf("producer %d: %d popped after %d", p, i, last[p])
+				} else {
+					last[p] = i
+				}
+				mu.Lock()
+				if seen[v] {
+					t.Errorf("duplicate value %d", v)
+				}
+				seen[v] = true
+				got++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	cwg.Wait()
+
+	for v, ok := range seen {
+		if !ok {
+			t.Fatalf("value %d lost", v)
+		}
+	}
+}
+
+func benchmarkContended(b *testing.B, push func(int), pop func()) {
+	b.SetParallelism(4)
+	b.RunParallel(func(pb *testing.PB) {
+		var i int
+		for pb.Next() {
+			push(i)
+			pop()
+			i++
+		}
+	})
+}
+
+func BenchmarkMPMCQueueContended(b *testing.B) {
+	q := NewMPMC[int](1024)
+	benchmarkContended(b, func(v int) { q.Push(v) }, func() { q.Pop() })
+}
+
+func BenchmarkSyncQueueContended(b *testing.B) {
+	q := NewSync[int]()
+	benchmarkContended(b, q.Push, func() { q.Pop() })
+}
@@ -5,7 +5,11 @@
 // Package queue implements a generic queue data structure.
 package queue
 
-import "sync"
+import (
+	"context"
+	"sync"
+	"time"
+)
 
 // Queue is a generic queue of any.
 // Items are Pushed to end of list and popped from the front.
@@ -36,6 +40,23 @@ func New[V any]() *Queue[V] { return &Queue[V]{} }
 //	q.Push(2)
 func (self *Queue[V]) Push(v V) { self.items = append(self.items, v) }
 
+// PushMany pushes vs to end of queue in order.
+//
+// Example:
+//
+//	q := New[int]()
+//	q.PushMany(1, 2, 3)
+func (self *Queue[V]) PushMany(vs ...V) { self.items = append(self.items, vs...) }
+
+// Len returns the number of items in the queue.
+//
+// Example:
+//
+//	q := New[int]()
+//	q.Push(1)
+//	l := q.Len() // l == 1
+func (self *Queue[V]) Len() int { return len(self.items) }
+
 // Pop returns item from the start of the queue and truth if one was found.
 // Returned value should be ignored if truth if false, it is the zero value of
 // Queue generic type.
@@ -55,27 +76,96 @@ func (self *Queue[V]) Pop() (v V, b bool) {
 	return
 }
 
+// unpop returns v to the start of the queue.
+func (self *Queue[V]) unpop(v V) {
+	self.items = append(self.items, v)
+	copy(self.items[1:], self.items)
+	self.items[0] = v
+}
+
+// PopN pops up to max items from the start of the queue and returns them in
+// queue order. It returns nil if the queue is empty or max < 1.
+//
+// Example:
+//
+//	q := New[int]()
+//	q.PushMany(1, 2, 3)
+//	out := q.PopN(2) // out == []int{1, 2}
+func (self *Queue[V]) PopN(max int) (out []V) {
+	if max > len(self.items) {
+		max = len(self.items)
+	}
+	if max < 1 {
+		return nil
+	}
+	out = make([]V, max)
+	copy(out, self.items)
+	clear(self.items[:max])
+	self.items = self.items[max:]
+	return
+}
+
 // SyncQueue is concurrency safe Queue.
 //
 // Example:
 //
-//	q := &SyncQueue[int]{q: New[int]()}
+//	q := NewSync[int]()
 //	go q.Push(1)
 //	v, ok := q.Pop()
 type SyncQueue[V any] struct {
 	mu sync.Mutex
 	q  *Queue[V]
+	// wait is closed and unset on push to wake consumers waiting for items.
+	wait chan struct{}
+}
+
+// NewSync returns a new [SyncQueue] of V.
+//
+// Example:
+//
+//	q := NewSync[int]()
+func NewSync[V any]() *SyncQueue[V] { return &SyncQueue[V]{q: New[V]()} }
+
+// Len returns the number of items in the queue.
+//
+// Example:
+//
+//	q := NewSync[int]()
+//	q.Push(1)
+//	l := q.Len() // l == 1
+func (self *SyncQueue[V]) Len() (l int) {
+	self.mu.Lock()
+	l = self.q.Len()
+	self.mu.Unlock()
+	return
 }
 
 // Push pushes v to end of queue.
 //
 // Example:
 //
-//	q := &SyncQueue[int]{q: New[int]()}
+//	q := NewSync[int]()
 //	q.Push(1)
 func (self *SyncQueue[V]) Push(v V) {
 	self.mu.Lock()
 	self.q.Push(v)
+	self.notify()
+	self.mu.Unlock()
+}
+
+// PushMany pushes vs to end of queue in order under a single lock.
+//
+// Example:
+//
+//	q := NewSync[int]()
+//	q.PushMany(1, 2, 3)
+func (self *SyncQueue[V]) PushMany(vs ...V) {
+	if len(vs) == 0 {
+		return
+	}
+	self.mu.Lock()
+	self.q.PushMany(vs...)
+	self.notify()
 	self.mu.Unlock()
 }
 
@@ -85,7 +175,7 @@ func (self *SyncQueue[V]) Push(v V) {
 //
 // Example:
 //
-//	q := &SyncQueue[int]{q: New[int]()}
+//	q := NewSync[int]()
 //	q.Push(1)
 //	v, ok := q.Pop() // v == 1, ok == true
 func (self *SyncQueue[V]) Pop() (v V, b bool) {
@@ -94,3 +184,84 @@ func (self *SyncQueue[V]) Pop() (v V, b bool) {
 	self.mu.Unlock()
 	return
 }
+
+// PopN pops up to max items from the start of the queue under a single lock
+// and returns them in queue order. It returns nil if the queue is empty or
+// max < 1.
+//
+// Example:
+//
+//	q := NewSync[int]()
+//	q.PushMany(1, 2, 3)
+//	out := q.PopN(2) // out == []int{1, 2}
+func (self *SyncQueue[V]) PopN(max int) (out []V) {
+	self.mu.Lock()
+	out = self.q.PopN(max)
+	self.mu.Unlock()
+	return
+}
+
+// DrainBatch collects a batch of up to maxItems items from the queue.
+//
+// It keeps collecting items until the batch holds maxItems items or maxWait
+// has elapsed since the call, whichever comes first. If no items arrive in
+// maxWait it returns an empty batch and a nil error. A maxWait <= 0 waits
+// for at least one item and returns whatever is available once it arrives.
+//
+// If ctx is done DrainBatch returns the items collected so far, which are
+// removed from the queue, and ctx.Err().
+//
+// Example:
+//
+//	q := NewSync[int]()
+//	go q.PushMany(1, 2, 3)
+//	batch, err := q.DrainBatch(ctx, 100, 10*time.Millisecond)
+func (self *SyncQueue[V]) DrainBatch(ctx context.Context, maxItems int, maxWait time.Duration) (batch []V, err error) {
+	if maxItems < 1 {
+		return nil, nil
+	}
+	var deadline <-chan time.Time
+	if maxWait > 0 {
+		var timer = time.NewTimer(maxWait)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	for {
+		self.mu.Lock()
+		batch = append(batch, self.q.PopN(maxItems-len(batch))...)
+		if len(batch) >= maxItems || len(batch) > 0 && maxWait <= 0 {
+			self.mu.Unlock()
+			return
+		}
+		var wait = self.waiter()
+		self.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return batch, ctx.Err()
+		case <-deadline:
+			self.mu.Lock()
+			batch = append(batch, self.q.PopN(maxItems-len(batch))...)
+			self.mu.Unlock()
+			return
+		case <-wait:
+		}
+	}
+}
+
+// waiter returns a channel that is closed on next push.
+// Caller must hold the lock.
+func (self *SyncQueue[V]) waiter() chan struct{} {
+	if self.wait == nil {
+		self.wait = make(chan struct{})
+	}
+	return self.wait
+}
+
+// notify wakes all waiters. Caller must hold the lock.
+func (self *SyncQueue[V]) notify() {
+	if self.wait != nil {
+		close(self.wait)
+		self.wait = nil
+	}
+}
@@ -0,0 +1,179 @@
+package queue
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	q := New[int]()
+	if _, ok := q.Pop(); ok {
+		t.Fatal("expected empty queue")
+	}
+	for i := 0; i < 3; i++ {
+		q.Push(i)
+	}
+	if l := q.Len(); l != 3 {
+		t.Fatalf("expected len 3, got %d", l)
+	}
+	for i := 0; i < 3; i++ {
+		if v, ok := q.Pop(); !ok || v != i {
+			t.Fatalf("expected (%d, true), got (%d, %t)", i, v, ok)
+		}
+	}
+	if l := q.Len(); l != 0 {
+		t.Fatalf("expected len 0, got %d", l)
+	}
+}
+
+func TestSyncQueue(t *testing.T) {
+	q := NewSync[int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			q.Push(i)
+		}(i)
+	}
+	wg.Wait()
+	if l := q.Len(); l != 100 {
+		t.Fatalf("expected len 100, got %d", l)
+	}
+	var seen = make(map[int]bool)
+	for {
+		v, ok := q.Pop()
+		if !ok {
+			break
+		}
+		seen[v] = true
+	}
+	if len(seen) != 100 {
+		t.Fatalf("expected 100 distinct items, got %d", len(seen))
+	}
+}
+
+func BenchmarkQueuePushPop(b *testing.B) {
+	q := New[int]()
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+		q.Pop()
+	}
+}
+
+func BenchmarkSyncQueuePushPop(b *testing.B) {
+	q := NewSync[int]()
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+		q.Pop()
+	}
+}
+
+func TestQueuePushManyPopN(t *testing.T) {
+	q := New[int]()
+	if out := q.PopN(1); out != nil {
+		t.Fatalf("expected nil, got %v", out)
+	}
+	q.PushMany(1, 2, 3, 4)
+	if out := q.PopN(0); out != nil {
+		t.Fatalf("expected nil, got %v", out)
+	}
+	if out := q.PopN(3); !slices.Equal(out, []int{1, 2, 3}) {
+		t.Fatalf("expected [1 2 3], got %v", out)
+	}
+	if out := q.PopN(3); !slices.Equal(out, []int{4}) {
+		t.Fatalf("expected [4], got %v", out)
+	}
+	if l := q.Len(); l != 0 {
+		t.Fatalf("expected len 0, got %d", l)
+	}
+}
+
+func TestSyncQueueDrainBatch(t *testing.T) {
+	t.Run("Full", func(t *testing.T) {
+		q := NewSync[int]()
+		q.PushMany(1, 2, 3, 4, 5)
+		batch, err := q.DrainBatch(context.Background(), 3, time.Hour)
+		if err != nil || !slices.Equal(batch, []int{1, 2, 3}) {
+			t.Fatalf("expected ([1 2 3], nil), got (%v, %v)", batch, err)
+		}
+		if out := q.PopN(10); !slices.Equal(out, []int{4, 5}) {
+			t.Fatalf("expected [4 5], got %v", out)
+		}
+	})
+
+	t.Run("Wait", func(t *testing.T) {
+		q := NewSync[int]()
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			q.Push(1)
+			q.Push(2)
+		}()
+		var start = time.Now()
+		batch, err := q.DrainBatch(context.Background(), 10, 20*time.Millisecond)
+		if err != nil || !slices.Equal(batch, []int{1, 2}) {
+			t.Fatalf("expected ([1 2], nil), got (%v, %v)", batch, err)
+		}
+		if time.Since(start) < 20*time.Millisecond {
+			t.Fatal("batch returned before wait elapsed")
+		}
+
+		// An idle queue returns an empty batch once the wait elapses.
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		start = time.Now()
+		batch, err = q.DrainBatch(ctx, 10, 20*time.Millisecond)
+		if err != nil || len(batch) != 0 {
+			t.Fatalf("expected ([], nil), got (%v, %v)", batch, err)
+		}
+		if d := time.Since(start); d < 20*time.Millisecond || d >= time.Second {
+			t.Fatalf("expected return after wait elapsed, got %v", d)
+		}
+	})
+
+	t.Run("FillsWhileWaiting", func(t *testing.T) {
+		q := NewSync[int]()
+		q.Push(1)
+		go func() {
+			for i := 2; i <= 4; i++ {
+				time.Sleep(time.Millisecond)
+				q.Push(i)
+			}
+		}()
+		batch, err := q.DrainBatch(context.Background(), 4, time.Hour)
+		if err != nil || !slices.Equal(batch, []int{1, 2, 3, 4}) {
+			t.Fatalf("expected ([1 2 3 4], nil), got (%v, %v)", batch, err)
+		}
+	})
+
+	t.Run("NoWait", func(t *testing.T) {
+		q := NewSync[int]()
+		q.PushMany(1, 2)
+		batch, err := q.DrainBatch(context.Background(), 10, 0)
+		if err != nil || !slices.Equal(batch, []int{1, 2}) {
+			t.Fatalf("expected ([1 2], nil), got (%v, %v)", batch, err)
+		}
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		q := NewSync[int]()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		batch, err := q.DrainBatch(ctx, 10, time.Hour)
+		if err != context.DeadlineExceeded || len(batch) != 0 {
+			t.Fatalf("expected ([], deadline exceeded), got (%v, %v)", batch, err)
+		}
+	})
+}
+
+func BenchmarkSyncQueuePushManyPopN(b *testing.B) {
+	q := NewSync[int]()
+	var items = make([]int, 64)
+	for i := 0; i < b.N; i++ {
+		q.PushMany(items...)
+		q.PopN(64)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package stack
+
+// Immutable is a persistent stack of T.
+//
+// Push and Pop never modify a stack, they return a new version that shares
+// all items below the top with the version it was derived from. Every
+// version stays valid, so keeping a snapshot is a copy of the value and
+// costs O(1). Immutable values are safe for concurrent use.
+//
+// The zero value is an empty stack ready to use.
+//
+// Example:
+//
+//	var s Immutable[int]
+//	s1 := s.Push(1)
+//	s2 := s1.Push(2)
+//	s3, v, _ := s2.Pop() // v == 2, s3 has the same items as s1
+//	v, _ = s2.Peek()     // v == 2, s2 is unchanged
+type Immutable[T any] struct {
+	head *immutableNode[T]
+}
+
+// immutableNode is an item of an [Immutable] stack.
+type immutableNode[T any] struct {
+	item T
+	next *immutableNode[T]
+	size int
+}
+
+// NewImmutable returns a new empty [Immutable] stack.
+func NewImmutable[T any]() Immutable[T] { return Immutable[T]{} }
+
+// Size returns number of items on the stack.
+func (self Immutable[T]) Size() int {
+	if self.head == nil {
+		return 0
+	}
+	return self.head.size
+}
+
+// Empty returns truth if the stack has no items.
+func (self Immutable[T]) Empty() bool { return self.head == nil }
+
+// Peek returns the top item of the stack and truth if the stack was not
+// empty.
+func (self Immutable[T]) Peek() (out T, ok bool) {
+	if ok = self.head != nil; ok {
+		out = self.head.item
+	}
+	return
+}
+
+// Push returns a new stack with item on top of the items of this stack.
+//
+// Example:
+//
+//	s := NewImmutable[int]()
+//	s1 := s.Push(1) // s.Size() == 0, s1.Size() == 1
+func (self Immutable[T]) Push(item T) Immutable[T] {
+	return Immutable[T]{&immutableNode[T]{item: item, next: self.head, size: self.Size() + 1}}
+}
+
+// Pop returns a new stack without the top item of this stack, the top item
+// and truth if the stack was not empty. If the stack is empty it is
+// returned as is.
+//
+// Example:
+//
+//	s := NewImmutable[int]().Push(1)
+//	rest, v, ok := s.Pop() // rest.Size() == 0, v == 1, ok == true
+func (self Immutable[T]) Pop() (rest Immutable[T], out T, ok bool) {
+	if self.head == nil {
+		return self, out, false
+	}
+	return Immutable[T]{self.head.next}, self.head.item, true
+}
+
+// EnumTopDown calls f for each item on the stack from the top to the bottom
+// until all items have been enumerated or f returns false.
+func (self Immutable[T]) EnumTopDown(f func(item T) bool) {
+	for n := self.head; n != nil; n = n.next {
+		if !f(n.item) {
+			break
+		}
+	}
+}
+
+// Items returns the items of the stack from the bottom to the top.
+func (self Immutable[T]) Items() (out []T) {
+	out = make([]T, self.Size())
+	var i = len(out) - 1
+	for n := self.head; n != nil; n = n.next {
+		out[i] = n.item
+		i--
+	}
+	return
+}
@@ -0,0 +1,57 @@
+package stack
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestImmutable(t *testing.T) {
+	var s Immutable[int]
+	if !s.Empty() || s.Size() != 0 {
+		t.Fatal("expected empty stack")
+	}
+	if _, ok := s.Peek(); ok {
+		t.Fatal("expected empty stack")
+	}
+	if rest, _, ok := s.Pop(); ok || !rest.Empty() {
+		t.Fatal("expected empty stack")
+	}
+
+	s1 := s.Push(1)
+	s2 := s1.Push(2)
+	s3 := s1.Push(3)
+
+	if !s.Empty() || s1.Size() != 1 || s2.Size() != 2 || s3.Size() != 2 {
+		t.Fatal("push modified a previous version")
+	}
+	if items := s2.Items(); !slices.Equal(items, []int{1, 2}) {
+		t.Fatalf("expected [1 2], got %v", items)
+	}
+	if items := s3.Items(); !slices.Equal(items, []int{1, 3}) {
+		t.Fatalf("expected [1 3], got %v", items)
+	}
+
+	rest, v, ok := s2.Pop()
+	if !ok || v != 2 || rest.Size() != 1 {
+		t.Fatalf("expected (2, true) with 1 left, got (%d, %t) with %d", v, ok, rest.Size())
+	}
+	if v, _ := s2.Peek(); v != 2 {
+		t.Fatalf("pop modified a previous version, peek %d", v)
+	}
+
+	var out []int
+	s2.EnumTopDown(func(item int) bool {
+		out = append(out, item)
+		return true
+	})
+	if !slices.Equal(out, []int{2, 1}) {
+		t.Fatalf("expected [2 1], got %v", out)
+	}
+}
+
+func BenchmarkImmutablePush(b *testing.B) {
+	s := NewImmutable[int]()
+	for i := 0; i < b.N; i++ {
+		s = s.Push(i)
+	}
+}
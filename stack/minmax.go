@@ -0,0 +1,83 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package stack
+
+// MinMaxStack is a [Stack] that reports its minimum and maximum items in
+// O(1). Items are ordered by a compare function that returns a negative
+// number if a < b, a positive number if a > b and zero if they are equal,
+// such as cmp.Compare.
+//
+// Example:
+//
+//	s := NewMinMax(cmp.Compare[int])
+//	s.Push(2)
+//	s.Push(1)
+//	s.Push(3)
+//	min, _ := s.Min() // min == 1
+//	max, _ := s.Max() // max == 3
+type MinMaxStack[T any] struct {
+	s   *Stack[minMaxEntry[T]]
+	cmp func(a, b T) int
+}
+
+// minMaxEntry is an item of a [MinMaxStack] with the minimum and maximum of
+// all items up to and including it.
+type minMaxEntry[T any] struct {
+	item, min, max T
+}
+
+// NewMinMax returns a new [MinMaxStack] ordered by cmp.
+func NewMinMax[T any](cmp func(a, b T) int) *MinMaxStack[T] {
+	return &MinMaxStack[T]{s: New[minMaxEntry[T]](), cmp: cmp}
+}
+
+// Size returns number of items on the stack.
+func (self *MinMaxStack[T]) Size() int { return self.s.Size() }
+
+// Push pushes an item to the stack.
+func (self *MinMaxStack[T]) Push(item T) {
+	var entry = minMaxEntry[T]{item, item, item}
+	if top, ok := self.s.PeekOk(); ok {
+		if self.cmp(top.min, item) < 0 {
+			entry.min = top.min
+		}
+		if self.cmp(top.max, item) > 0 {
+			entry.max = top.max
+		}
+	}
+	self.s.Push(entry)
+}
+
+// Pop removes an item from the stack and returns it and truth if the stack
+// was not empty.
+func (self *MinMaxStack[T]) Pop() (out T, ok bool) {
+	var entry minMaxEntry[T]
+	entry, ok = self.s.PopOk()
+	return entry.item, ok
+}
+
+// Peek returns the last item added to the stack and truth if the stack was
+// not empty.
+func (self *MinMaxStack[T]) Peek() (out T, ok bool) {
+	var entry minMaxEntry[T]
+	entry, ok = self.s.PeekOk()
+	return entry.item, ok
+}
+
+// Min returns the smallest item on the stack and truth if the stack was not
+// empty.
+func (self *MinMaxStack[T]) Min() (out T, ok bool) {
+	var entry minMaxEntry[T]
+	entry, ok = self.s.PeekOk()
+	return entry.min, ok
+}
+
+// Max returns the largest item on the stack and truth if the stack was not
+// empty.
+func (self *MinMaxStack[T]) Max() (out T, ok bool) {
+	var entry minMaxEntry[T]
+	entry, ok = self.s.PeekOk()
+	return entry.max, ok
+}
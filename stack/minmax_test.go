@@ -0,0 +1,48 @@
+package stack
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestMinMaxStack(t *testing.T) {
+	s := NewMinMax(cmp.Compare[int])
+	if _, ok := s.Min(); ok {
+		t.Fatal("expected empty stack")
+	}
+	if _, ok := s.Max(); ok {
+		t.Fatal("expected empty stack")
+	}
+
+	var items []int
+	for i := 0; i < 1000; i++ {
+		if rand.Intn(3) == 0 && len(items) > 0 {
+			v, _ := s.Pop()
+			if want := items[len(items)-1]; v != want {
+				t.Fatalf("expected pop %d, got %d", want, v)
+			}
+			items = items[:len(items)-1]
+		} else {
+			var v = rand.Intn(100)
+			s.Push(v)
+			items = append(items, v)
+		}
+		if s.Size() != len(items) {
+			t.Fatalf("expected size %d, got %d", len(items), s.Size())
+		}
+		if len(items) == 0 {
+			continue
+		}
+		if v, _ := s.Min(); v != slices.Min(items) {
+			t.Fatalf("expected min %d, got %d", slices.Min(items), v)
+		}
+		if v, _ := s.Max(); v != slices.Max(items) {
+			t.Fatalf("expected max %d, got %d", slices.Max(items), v)
+		}
+		if v, _ := s.Peek(); v != items[len(items)-1] {
+			t.Fatalf("expected peek %d, got %d", items[len(items)-1], v)
+		}
+	}
+}
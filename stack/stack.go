@@ -1,36 +1,396 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package stack implements a generic stack data structure.
 package stack
 
-// Stack[T] is a stack of [Node].
-// Used for keeping parent/child relationship during HTML tokenization.
+import "sync"
+
+// Stack[T] is a last-in first-out stack of T.
+// Used for keeping parent/child relationship during HTML tokenization, see
+// [TreeBuilder].
+//
+// The zero value is an empty stack ready to use. For a stack that holds a
+// limited number of items see [BoundedStack].
+//
+// Example:
+//
+//	s := New[int]()
+//	s.Push(1)
+//	s.Push(2)
+//	v, ok := s.PopOk() // v == 2, ok == true
 type Stack[T any] []T
 
-// NewStack returns a new [Stack[T]].
+// New returns a new [Stack[T]].
 func New[T any]() *Stack[T] {
 	var val = Stack[T](make([]T, 0))
 	return &val
 }
 
-// Size returns number of strings on the stack.
-func (self *Stack[T]) Size() int { return len([]T(*self)) }
+// Size returns number of items on the stack.
+func (self *Stack[T]) Size() int { return len(*self) }
 
-// Peek returns the last node added to the stack or nil if queue is empty.
+// Peek returns the last item added to the stack or a zero value if stack is
+// empty.
 func (self *Stack[T]) Peek() (out T) {
-	if self.Size() < 1 {
-		return *new(T)
+	out, _ = self.PeekOk()
+	return
+}
+
+// PeekOk returns the last item added to the stack and truth if the stack was
+// not empty.
+//
+// Example:
+//
+//	s := New[int]()
+//	v, ok := s.PeekOk() // v == 0, ok == false
+//	s.Push(0)
+//	v, ok = s.PeekOk()  // v == 0, ok == true
+func (self *Stack[T]) PeekOk() (out T, ok bool) {
+	if ok = len(*self) > 0; ok {
+		out = (*self)[len(*self)-1]
 	}
-	return []T(*self)[self.Size()-1]
+	return
 }
 
-// Push pushes a node to the stack.
+// PeekN returns up to n items from the top of the stack without removing
+// them, topmost first.
+//
+// Example:
+//
+//	s := New[int]()
+//	s.Push(1)
+//	s.Push(2)
+//	s.Push(3)
+//	out := s.PeekN(2) // out == []int{3, 2}
+func (self *Stack[T]) PeekN(n int) (out []T) {
+	var items = *self
+	n = min(max(n, 0), len(items))
+	out = make([]T, 0, n)
+	for i := len(items) - 1; i >= len(items)-n; i-- {
+		out = append(out, items[i])
+	}
+	return
+}
+
+// Push pushes an item to the stack.
 func (self *Stack[T]) Push(item T) { *self = append(*self, item) }
 
-// Pop removes a node from the stack and returns it.
+// Pop removes an item from the stack and returns it or a zero value if
+// stack is empty.
 func (self *Stack[T]) Pop() (out T) {
-	var l = self.Size()
-	if l < 1 {
-		return *new(T)
+	out, _ = self.PopOk()
+	return
+}
+
+// PopOk removes an item from the stack and returns it and truth if the stack
+// was not empty.
+//
+// Example:
+//
+//	s := New[int]()
+//	s.Push(0)
+//	v, ok := s.PopOk() // v == 0, ok == true
+//	v, ok = s.PopOk()  // v == 0, ok == false
+func (self *Stack[T]) PopOk() (out T, ok bool) {
+	var l = len(*self)
+	if ok = l > 0; !ok {
+		return
+	}
+	out = (*self)[l-1]
+	(*self)[l-1] = *new(T)
+	*self = (*self)[:l-1]
+	return
+}
+
+// PopBottom removes the first item added to the stack and returns it and
+// truth if the stack was not empty. It is useful for trimming old items off
+// a stack that grows without bound.
+//
+// Example:
+//
+//	s := New[int]()
+//	s.Push(1)
+//	s.Push(2)
+//	v, ok := s.PopBottom() // v == 1, ok == true
+func (self *Stack[T]) PopBottom() (out T, ok bool) {
+	if ok = len(*self) > 0; !ok {
+		return
 	}
-	out = []T(*self)[l-1]
-	*self = []T(*self)[:l-1]
+	out = (*self)[0]
+	(*self)[0] = *new(T)
+	*self = (*self)[1:]
+	return
+}
+
+// PopN removes up to n items from the top of the stack and returns them,
+// topmost first.
+//
+// Example:
+//
+//	s := New[int]()
+//	s.Push(1)
+//	s.Push(2)
+//	s.Push(3)
+//	out := s.PopN(2) // out == []int{3, 2}, s.Size() == 1
+func (self *Stack[T]) PopN(n int) (out []T) {
+	out = self.PeekN(n)
+	var l = len(*self) - len(out)
+	clear((*self)[l:])
+	*self = (*self)[:l]
+	return
+}
+
+// Clear removes all items from the stack.
+func (self *Stack[T]) Clear() {
+	clear(*self)
+	*self = (*self)[:0]
+}
+
+// EnumTopDown calls f for each item on the stack from the top to the bottom
+// until all items have been enumerated or f returns false.
+//
+// Example:
+//
+//	s := New[int]()
+//	s.Push(1)
+//	s.Push(2)
+//	s.EnumTopDown(func(item int) bool {
+//		fmt.Println(item) // Prints 2 then 1.
+//		return true
+//	})
+func (self *Stack[T]) EnumTopDown(f func(item T) bool) {
+	for i := len(*self) - 1; i >= 0; i-- {
+		if !f((*self)[i]) {
+			break
+		}
+	}
+}
+
+// EnumBottomUp calls f for each item on the stack from the bottom to the top
+// until all items have been enumerated or f returns false.
+//
+// Example:
+//
+//	s := New[int]()
+//	s.Push(1)
+//	s.Push(2)
+//	s.EnumBottomUp(func(item int) bool {
+//		fmt.Println(item) // Prints 1 then 2.
+//		return true
+//	})
+func (self *Stack[T]) EnumBottomUp(f func(item T) bool) {
+	for _, item := range *self {
+		if !f(item) {
+			break
+		}
+	}
+}
+
+// BoundedStack[T] is a [Stack[T]] that holds a limited number of items.
+// Pushing to a full stack fails.
+//
+// The zero value is an empty, unbounded stack ready to use.
+//
+// Example:
+//
+//	s := NewBounded[int](1)
+//	ok := s.Push(1) // ok == true
+//	ok = s.Push(2)  // ok == false
+type BoundedStack[T any] struct {
+	s        Stack[T]
+	capacity int
+}
+
+// NewBounded returns a new [BoundedStack[T]] that holds at most capacity
+// items. A capacity <= 0 means unbounded.
+func NewBounded[T any](capacity int) *BoundedStack[T] {
+	return &BoundedStack[T]{s: make([]T, 0), capacity: max(capacity, 0)}
+}
+
+// Size returns number of items on the stack.
+func (self *BoundedStack[T]) Size() int { return self.s.Size() }
+
+// Cap returns the maximum number of items the stack holds or 0 if the stack
+// is unbounded.
+func (self *BoundedStack[T]) Cap() int { return self.capacity }
+
+// Peek returns the last item added to the stack or a zero value if stack is
+// empty.
+func (self *BoundedStack[T]) Peek() T { return self.s.Peek() }
+
+// PeekOk returns the last item added to the stack and truth if the stack was
+// not empty.
+func (self *BoundedStack[T]) PeekOk() (T, bool) { return self.s.PeekOk() }
+
+// PeekN returns up to n items from the top of the stack without removing
+// them, topmost first.
+func (self *BoundedStack[T]) PeekN(n int) []T { return self.s.PeekN(n) }
+
+// Push pushes an item to the stack and returns truth if it was pushed or
+// false if the stack is full.
+func (self *BoundedStack[T]) Push(item T) (ok bool) {
+	if self.capacity > 0 && len(self.s) >= self.capacity {
+		return false
+	}
+	self.s.Push(item)
+	return true
+}
+
+// Pop removes an item from the stack and returns it or a zero value if
+// stack is empty.
+func (self *BoundedStack[T]) Pop() T { return self.s.Pop() }
+
+// PopOk removes an item from the stack and returns it and truth if the stack
+// was not empty.
+func (self *BoundedStack[T]) PopOk() (T, bool) { return self.s.PopOk() }
+
+// PopBottom removes the first item added to the stack and returns it and
+// truth if the stack was not empty.
+func (self *BoundedStack[T]) PopBottom() (T, bool) { return self.s.PopBottom() }
+
+// PopN removes up to n items from the top of the stack and returns them,
+// topmost first.
+func (self *BoundedStack[T]) PopN(n int) []T { return self.s.PopN(n) }
+
+// Clear removes all items from the stack.
+func (self *BoundedStack[T]) Clear() { self.s.Clear() }
+
+// EnumTopDown calls f for each item on the stack from the top to the bottom
+// until all items have been enumerated or f returns false.
+func (self *BoundedStack[T]) EnumTopDown(f func(item T) bool) { self.s.EnumTopDown(f) }
+
+// EnumBottomUp calls f for each item on the stack from the bottom to the top
+// until all items have been enumerated or f returns false.
+func (self *BoundedStack[T]) EnumBottomUp(f func(item T) bool) { self.s.EnumBottomUp(f) }
+
+// SyncStack is the concurrency safe version of [Stack] and [BoundedStack].
+//
+// Example:
+//
+//	s := NewSync[int]()
+//	go s.Push(1)
+//	v, ok := s.PopOk()
+type SyncStack[T any] struct {
+	mu sync.Mutex
+	s  *BoundedStack[T]
+}
+
+// NewSync returns a new unbounded [SyncStack[T]].
+func NewSync[T any]() *SyncStack[T] { return &SyncStack[T]{s: NewBounded[T](0)} }
+
+// NewBoundedSync returns a new [SyncStack[T]] that holds at most capacity
+// items. A capacity <= 0 means unbounded.
+func NewBoundedSync[T any](capacity int) *SyncStack[T] {
+	return &SyncStack[T]{s: NewBounded[T](capacity)}
+}
+
+// Size returns number of items on the stack.
+func (self *SyncStack[T]) Size() (out int) {
+	self.mu.Lock()
+	out = self.s.Size()
+	self.mu.Unlock()
+	return
+}
+
+// Cap returns the maximum number of items the stack holds or 0 if the stack
+// is unbounded.
+func (self *SyncStack[T]) Cap() int { return self.s.Cap() }
+
+// Peek returns the last item added to the stack or a zero value if stack is
+// empty.
+func (self *SyncStack[T]) Peek() (out T) {
+	self.mu.Lock()
+	out = self.s.Peek()
+	self.mu.Unlock()
+	return
+}
+
+// PeekOk returns the last item added to the stack and truth if the stack was
+// not empty.
+func (self *SyncStack[T]) PeekOk() (out T, ok bool) {
+	self.mu.Lock()
+	out, ok = self.s.PeekOk()
+	self.mu.Unlock()
+	return
+}
+
+// PeekN returns up to n items from the top of the stack without removing
+// them, topmost first.
+func (self *SyncStack[T]) PeekN(n int) (out []T) {
+	self.mu.Lock()
+	out = self.s.PeekN(n)
+	self.mu.Unlock()
 	return
 }
+
+// Push pushes an item to the stack and returns truth if it was pushed or
+// false if the stack is bounded and full.
+func (self *SyncStack[T]) Push(item T) (ok bool) {
+	self.mu.Lock()
+	ok = self.s.Push(item)
+	self.mu.Unlock()
+	return
+}
+
+// Pop removes an item from the stack and returns it or a zero value if
+// stack is empty.
+func (self *SyncStack[T]) Pop() (out T) {
+	self.mu.Lock()
+	out = self.s.Pop()
+	self.mu.Unlock()
+	return
+}
+
+// PopOk removes an item from the stack and returns it and truth if the stack
+// was not empty.
+func (self *SyncStack[T]) PopOk() (out T, ok bool) {
+	self.mu.Lock()
+	out, ok = self.s.PopOk()
+	self.mu.Unlock()
+	return
+}
+
+// PopBottom removes the first item added to the stack and returns it and
+// truth if the stack was not empty.
+func (self *SyncStack[T]) PopBottom() (out T, ok bool) {
+	self.mu.Lock()
+	out, ok = self.s.PopBottom()
+	self.mu.Unlock()
+	return
+}
+
+// PopN removes up to n items from the top of the stack and returns them,
+// topmost first.
+func (self *SyncStack[T]) PopN(n int) (out []T) {
+	self.mu.Lock()
+	out = self.s.PopN(n)
+	self.mu.Unlock()
+	return
+}
+
+// Clear removes all items from the stack.
+func (self *SyncStack[T]) Clear() {
+	self.mu.Lock()
+	self.s.Clear()
+	self.mu.Unlock()
+}
+
+// EnumTopDown calls f for each item on the stack from the top to the bottom
+// until all items have been enumerated or f returns false. The stack is
+// locked during enumeration so f must not call methods of the stack.
+func (self *SyncStack[T]) EnumTopDown(f func(item T) bool) {
+	self.mu.Lock()
+	self.s.EnumTopDown(f)
+	self.mu.Unlock()
+}
+
+// EnumBottomUp calls f for each item on the stack from the bottom to the top
+// until all items have been enumerated or f returns false. The stack is
+// locked during enumeration so f must not call methods of the stack.
+func (self *SyncStack[T]) EnumBottomUp(f func(item T) bool) {
+	self.mu.Lock()
+	self.s.EnumBottomUp(f)
+	self.mu.Unlock()
+}
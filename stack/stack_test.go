@@ -1,8 +1,9 @@
 package stack
 
 import (
+	"slices"
+	"sync"
 	"testing"
-
 )
 
 func TestStack(t *testing.T) {
@@ -68,6 +69,152 @@ func TestStack(t *testing.T) {
 	}
 }
 
+func TestStackOk(t *testing.T) {
+	var s Stack[int]
+	if _, ok := s.PeekOk(); ok {
+		t.Fatal("expected empty stack")
+	}
+	if _, ok := s.PopOk(); ok {
+		t.Fatal("expected empty stack")
+	}
+	s.Push(0)
+	if v, ok := s.PeekOk(); !ok || v != 0 {
+		t.Fatalf("expected (0, true), got (%d, %t)", v, ok)
+	}
+	if v, ok := s.PopOk(); !ok || v != 0 {
+		t.Fatalf("expected (0, true), got (%d, %t)", v, ok)
+	}
+	if _, ok := s.PopOk(); ok {
+		t.Fatal("expected empty stack")
+	}
+}
+
+func TestStackN(t *testing.T) {
+	s := New[int]()
+	for i := 1; i <= 4; i++ {
+		s.Push(i)
+	}
+	if out := s.PeekN(2); !slices.Equal(out, []int{4, 3}) {
+		t.Fatalf("expected [4 3], got %v", out)
+	}
+	if out := s.PeekN(-1); len(out) != 0 {
+		t.Fatalf("expected [], got %v", out)
+	}
+	if v, ok := s.PopBottom(); !ok || v != 1 {
+		t.Fatalf("expected (1, true), got (%d, %t)", v, ok)
+	}
+	s.Push(5)
+	if out := s.PopN(3); !slices.Equal(out, []int{5, 4, 3}) {
+		t.Fatalf("expected [5 4 3], got %v", out)
+	}
+	if out := s.PopN(3); !slices.Equal(out, []int{2}) {
+		t.Fatalf("expected [2], got %v", out)
+	}
+	if _, ok := s.PopBottom(); ok {
+		t.Fatal("expected empty stack")
+	}
+	if s.Size() != 0 {
+		t.Fatalf("expected size 0, got %d", s.Size())
+	}
+}
+
+func TestStackSlice(t *testing.T) {
+	s := New[int]()
+	s.Push(1)
+	s.Push(2)
+	if len(*s) != 2 || (*s)[0] != 1 {
+		t.Fatalf("expected [1 2], got %v", *s)
+	}
+	var push func(int) = s.Push
+	push(3)
+	if !slices.Equal(*s, []int{1, 2, 3}) {
+		t.Fatalf("expected [1 2 3], got %v", *s)
+	}
+}
+
+func TestStackClearAndEnum(t *testing.T) {
+	s := New[int]()
+	for i := 1; i <= 3; i++ {
+		s.Push(i)
+	}
+	var out []int
+	s.EnumTopDown(func(item int) bool {
+		out = append(out, item)
+		return true
+	})
+	if !slices.Equal(out, []int{3, 2, 1}) {
+		t.Fatalf("expected [3 2 1], got %v", out)
+	}
+	out = nil
+	s.EnumBottomUp(func(item int) bool {
+		out = append(out, item)
+		return len(out) < 2
+	})
+	if !slices.Equal(out, []int{1, 2}) {
+		t.Fatalf("expected [1 2], got %v", out)
+	}
+	s.Clear()
+	if s.Size() != 0 {
+		t.Fatalf("expected size 0, got %d", s.Size())
+	}
+}
+
+func TestStackBounded(t *testing.T) {
+	s := NewBounded[int](2)
+	if s.Cap() != 2 {
+		t.Fatalf("expected cap 2, got %d", s.Cap())
+	}
+	if !s.Push(1) || !s.Push(2) {
+		t.Fatal("expected push to succeed")
+	}
+	if s.Push(3) {
+		t.Fatal("expected push to fail on full stack")
+	}
+	s.Pop()
+	if !s.Push(3) {
+		t.Fatal("expected push to succeed")
+	}
+	if NewBounded[int](0).Cap() != 0 {
+		t.Fatal("expected unbounded stack")
+	}
+}
+
+func TestSyncStack(t *testing.T) {
+	s := NewSync[int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.Push(i)
+		}(i)
+	}
+	wg.Wait()
+	if s.Size() != 100 {
+		t.Fatalf("expected size 100, got %d", s.Size())
+	}
+	if out := s.PopN(10); len(out) != 10 {
+		t.Fatalf("expected 10 items, got %d", len(out))
+	}
+	var n int
+	s.EnumTopDown(func(int) bool {
+		n++
+		return true
+	})
+	if n != 90 {
+		t.Fatalf("expected 90 items, got %d", n)
+	}
+	s.Clear()
+	if _, ok := s.PopOk(); ok {
+		t.Fatal("expected empty stack")
+	}
+
+	b := NewBoundedSync[int](1)
+	if !b.Push(1) || b.Push(2) {
+		t.Fatal("expected bounded push semantics")
+	}
+}
+
 func BenchmarkNew(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		New[int]()
@@ -0,0 +1,216 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package stack
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Pos is a position in tokenizer input.
+type Pos struct {
+	// Offset is the byte offset, starting at 0.
+	Offset int
+	// Line is the line number, starting at 1.
+	Line int
+	// Column is the column number, starting at 1.
+	Column int
+}
+
+// String returns the position as "line:column".
+func (self Pos) String() string { return fmt.Sprintf("%d:%d", self.Line, self.Column) }
+
+// TreeNode is a node of a tree built by [TreeBuilder].
+type TreeNode[T any] struct {
+	// Value is the value given to the open or leaf event.
+	Value T
+	// Parent is the parent node, nil for the root.
+	Parent *TreeNode[T]
+	// Children are the child nodes in order of appearance.
+	Children []*TreeNode[T]
+	// Start is the position of the open or leaf event.
+	Start Pos
+	// End is the position of the event that closed the element, which may
+	// be an explicit close, an implied close or the end of input. For leaves
+	// it equals Start.
+	End Pos
+	// Leaf is true if the node was added by a leaf event.
+	Leaf bool
+}
+
+// TreeErrorKind is the kind of a [TreeError].
+type TreeErrorKind int
+
+const (
+	// UnexpectedClose is a close event that matches no open element.
+	UnexpectedClose TreeErrorKind = iota
+	// Unclosed is an element that was not explicitly closed before an
+	// ancestor was closed or input ended.
+	Unclosed
+)
+
+// TreeError describes a nesting problem found by [TreeBuilder].
+type TreeError[K comparable] struct {
+	// Kind is the kind of the error.
+	Kind TreeErrorKind
+	// Name is the name of the offending element.
+	Name K
+	// Pos is the position where the problem was found.
+	Pos Pos
+	// OpenPos is the position where an Unclosed element was opened.
+	OpenPos Pos
+}
+
+// Error implements error.
+func (self *TreeError[K]) Error() string {
+	if self.Kind == Unclosed {
+		return fmt.Sprintf("%s: element %v opened at %s not closed", self.Pos, self.Name, self.OpenPos)
+	}
+	return fmt.Sprintf("%s: unexpected close of %v", self.Pos, self.Name)
+}
+
+// TreeRules define implied close rules of a [TreeBuilder]. Nil rules imply
+// nothing, every element must be closed explicitly.
+type TreeRules[K comparable] struct {
+	// ClosedBy reports whether an open element named open is implicitly
+	// closed by an element named next opening as its child, e.g. an HTML
+	// "p" is closed by "p" and "div", an "li" by "li".
+	ClosedBy func(open, next K) bool
+	// OptionalEnd reports whether an element named name may be left
+	// unclosed and closed implicitly when its parent closes or input ends
+	// without reporting an error, e.g. HTML "li" and "p".
+	OptionalEnd func(name K) bool
+}
+
+// TreeBuilder builds a tree from a stream of open, close and leaf events as
+// produced by a tokenizer, tracking the open elements on a [Stack].
+//
+// Elements are identified by a name of type K derived from event values.
+// A close event closes the nearest open element of that name; elements
+// opened after it are closed implicitly and reported as [Unclosed] unless
+// their end is optional. A close event with no matching open element is
+// reported as [UnexpectedClose] and ignored.
+//
+// Example:
+//
+//	b := NewTreeBuilder(func(tag string) string { return tag }, nil)
+//	b.Open("html", Pos{})
+//	b.Open("body", Pos{})
+//	b.Leaf("text", Pos{})
+//	b.Close("body", Pos{})
+//	b.Close("html", Pos{})
+//	root, err := b.Finish(Pos{}) // root.Children[0].Value == "html"
+type TreeBuilder[K comparable, T any] struct {
+	name  func(T) K
+	rules TreeRules[K]
+	root  *TreeNode[T]
+	open  *Stack[*TreeNode[T]]
+	errs  []error
+}
+
+// NewTreeBuilder returns a new [TreeBuilder] that names elements with name
+// and applies rules. A nil rules implies no closes.
+func NewTreeBuilder[K comparable, T any](name func(T) K, rules *TreeRules[K]) *TreeBuilder[K, T] {
+	var out = &TreeBuilder[K, T]{
+		name: name,
+		root: &TreeNode[T]{},
+		open: New[*TreeNode[T]](),
+	}
+	if rules != nil {
+		out.rules = *rules
+	}
+	return out
+}
+
+// Depth returns the number of open elements.
+func (self *TreeBuilder[K, T]) Depth() int { return self.open.Size() }
+
+// Current returns the innermost open element or the root node if no
+// elements are open.
+func (self *TreeBuilder[K, T]) Current() *TreeNode[T] {
+	if node, ok := self.open.PeekOk(); ok {
+		return node
+	}
+	return self.root
+}
+
+// Errors returns the errors reported so far.
+func (self *TreeBuilder[K, T]) Errors() []error { return self.errs }
+
+// Open opens an element with value v at pos as a child of the current
+// element, first closing open elements implicitly closed by it.
+func (self *TreeBuilder[K, T]) Open(v T, pos Pos) {
+	if self.rules.ClosedBy != nil {
+		var name = self.name(v)
+		for {
+			var top, ok = self.open.PeekOk()
+			if !ok || !self.rules.ClosedBy(self.name(top.Value), name) {
+				break
+			}
+			self.open.Pop()
+			top.End = pos
+		}
+	}
+	var node = self.add(v, pos)
+	self.open.Push(node)
+}
+
+// Close closes the nearest open element named name at pos.
+func (self *TreeBuilder[K, T]) Close(name K, pos Pos) {
+	var depth, found = 0, false
+	self.open.EnumTopDown(func(node *TreeNode[T]) bool {
+		if found = self.name(node.Value) == name; !found {
+			depth++
+		}
+		return !found
+	})
+	if !found {
+		self.errs = append(self.errs, &TreeError[K]{Kind: UnexpectedClose, Name: name, Pos: pos})
+		return
+	}
+	for _, node := range self.open.PopN(depth) {
+		self.implicitClose(node, pos)
+	}
+	self.open.Pop().End = pos
+}
+
+// Leaf adds a leaf with value v at pos as a child of the current element.
+func (self *TreeBuilder[K, T]) Leaf(v T, pos Pos) {
+	var node = self.add(v, pos)
+	node.Leaf = true
+	node.End = pos
+}
+
+// Finish closes all open elements at end position pos and returns the root
+// node, whose children are the top level nodes, and all reported errors
+// joined. The builder is reset and can be reused.
+func (self *TreeBuilder[K, T]) Finish(pos Pos) (root *TreeNode[T], err error) {
+	for _, node := range self.open.PopN(self.open.Size()) {
+		self.implicitClose(node, pos)
+	}
+	root, err = self.root, errors.Join(self.errs...)
+	root.End = pos
+	self.root, self.errs = &TreeNode[T]{}, nil
+	return
+}
+
+// add appends a new node to the current element.
+func (self *TreeBuilder[K, T]) add(v T, pos Pos) (node *TreeNode[T]) {
+	var parent = self.Current()
+	node = &TreeNode[T]{Value: v, Parent: parent, Start: pos}
+	parent.Children = append(parent.Children, node)
+	return
+}
+
+// implicitClose closes node at pos and reports it unless its end is
+// optional.
+func (self *TreeBuilder[K, T]) implicitClose(node *TreeNode[T], pos Pos) {
+	node.End = pos
+	var name = self.name(node.Value)
+	if self.rules.OptionalEnd != nil && self.rules.OptionalEnd(name) {
+		return
+	}
+	self.errs = append(self.errs, &TreeError[K]{Kind: Unclosed, Name: name, Pos: pos, OpenPos: node.Start})
+}
@@ -0,0 +1,114 @@
+package stack
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// tagName names test elements by their tag.
+func tagName(tag string) string { return tag }
+
+// render returns the tree under node as nested parentheses.
+func render(node *TreeNode[string]) string {
+	var b strings.Builder
+	for i, child := range node.Children {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(child.Value)
+		if !child.Leaf {
+			b.WriteString("(" + render(child) + ")")
+		}
+	}
+	return b.String()
+}
+
+func at(line int) Pos { return Pos{Line: line, Column: 1} }
+
+func TestTreeBuilder(t *testing.T) {
+	b := NewTreeBuilder(tagName, nil)
+	b.Open("html", at(1))
+	b.Open("body", at(2))
+	if b.Depth() != 2 || b.Current().Value != "body" {
+		t.Fatalf("unexpected state, depth %d", b.Depth())
+	}
+	b.Leaf("text", at(3))
+	b.Close("body", at(4))
+	b.Close("html", at(5))
+
+	root, err := b.Finish(at(6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := render(root); s != "html(body(text))" {
+		t.Fatalf("unexpected tree %s", s)
+	}
+	var body = root.Children[0].Children[0]
+	if body.Parent != root.Children[0] || body.Start != at(2) || body.End != at(4) {
+		t.Fatalf("unexpected body node %+v", body)
+	}
+}
+
+func TestTreeBuilderErrors(t *testing.T) {
+	b := NewTreeBuilder(tagName, nil)
+	b.Open("div", at(1))
+	b.Open("span", at(2))
+	b.Close("p", at(3))
+	b.Close("div", at(4))
+	b.Open("b", at(5))
+
+	root, err := b.Finish(at(6))
+	if s := render(root); s != "div(span()) b()" {
+		t.Fatalf("unexpected tree %s", s)
+	}
+	var errs = b.Errors()
+	if errs != nil {
+		t.Fatal("expected builder to be reset")
+	}
+	var te *TreeError[string]
+	if !errors.As(err, &te) || te.Kind != UnexpectedClose || te.Name != "p" || te.Pos != at(3) {
+		t.Fatalf("unexpected first error %v", err)
+	}
+	for _, want := range []string{
+		"3:1: unexpected close of p",
+		"4:1: element span opened at 2:1 not closed",
+		"6:1: element b opened at 5:1 not closed",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestTreeBuilderImpliedClose(t *testing.T) {
+	var optional = map[string]bool{"li": true, "p": true}
+	b := NewTreeBuilder(tagName, &TreeRules[string]{
+		ClosedBy: func(open, next string) bool {
+			return (open == "li" && next == "li") || (open == "p" && (next == "p" || next == "ul"))
+		},
+		OptionalEnd: func(name string) bool { return optional[name] },
+	})
+	b.Open("p", at(1))
+	b.Leaf("a", at(1))
+	b.Open("p", at(2))
+	b.Leaf("b", at(2))
+	b.Open("ul", at(3))
+	b.Open("li", at(4))
+	b.Leaf("1", at(4))
+	b.Open("li", at(5))
+	b.Leaf("2", at(5))
+	b.Close("ul", at(6))
+	b.Open("p", at(7))
+
+	root, err := b.Finish(at(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := render(root); s != "p(a) p(b) ul(li(1) li(2)) p()" {
+		t.Fatalf("unexpected tree %s", s)
+	}
+	if end := root.Children[0].End; end != at(2) {
+		t.Fatalf("expected first p to end at 2:1, got %s", end)
+	}
+}
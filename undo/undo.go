@@ -0,0 +1,319 @@
+// Copyright 2025 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package undo implements an undo/redo history of reversible commands.
+package undo
+
+import (
+	"errors"
+
+	"github.com/vedranvuk/ds/stack"
+)
+
+var (
+	// ErrNothingToUndo is returned by [History.Undo] when there is no
+	// command to undo.
+	ErrNothingToUndo = errors.New("nothing to undo")
+	// ErrNothingToRedo is returned by [History.Redo] when there is no
+	// command to redo.
+	ErrNothingToRedo = errors.New("nothing to redo")
+	// ErrNoTransaction is returned when committing or rolling back without
+	// an open transaction.
+	ErrNoTransaction = errors.New("no open transaction")
+	// ErrInTransaction is returned when undoing or redoing while a
+	// transaction is open.
+	ErrInTransaction = errors.New("transaction open")
+)
+
+// Command is a reversible action.
+type Command interface {
+	// Do applies the command. It is called when the command is executed
+	// and each time it is redone.
+	Do() error
+	// Undo reverts the effects of Do.
+	Undo() error
+}
+
+// Sizer is implemented by commands that report their memory cost, in any
+// unit, for limiting history size with [Options.MaxSize]. Commands that do
+// not implement Sizer have a size of 0.
+type Sizer interface {
+	Size() int
+}
+
+// Options configure a [History].
+type Options struct {
+	// MaxDepth is the maximum number of commands kept for undo. When
+	// exceeded the oldest commands are discarded. If <= 0 depth is not
+	// limited.
+	MaxDepth int
+	// MaxSize is the maximum total size of commands kept for undo as
+	// reported by [Sizer]. When exceeded the oldest commands are discarded.
+	// A command larger than MaxSize is not kept at all. If <= 0 size is not
+	// limited.
+	MaxSize int
+}
+
+// History is an undo/redo history of [Command].
+//
+// Executing a command pushes it to the undo stack and clears the redo stack.
+// Undoing a command moves it to the redo stack and redoing it moves it back.
+// Commands executed between [History.Begin] and [History.Commit] are grouped
+// into a single command that is undone and redone as a whole.
+//
+// History is not safe for concurrent use.
+//
+// Example:
+//
+//	h := New(nil)
+//	h.Do(cmd)
+//	h.Undo() // reverts cmd
+//	h.Redo() // applies cmd again
+type History struct {
+	opts     Options
+	undo     *stack.Stack[Command]
+	redo     *stack.Stack[Command]
+	undoSize int
+	// open holds open transactions, innermost on top.
+	open *stack.Stack[*Group]
+}
+
+// New returns a new [History] configured by opts. A nil opts means no
+// limits.
+//
+// Example:
+//
+//	h := New(&Options{MaxDepth: 100})
+func New(opts *Options) *History {
+	var out = &History{
+		undo: stack.New[Command](),
+		redo: stack.New[Command](),
+		open: stack.New[*Group](),
+	}
+	if opts != nil {
+		out.opts = *opts
+	}
+	return out
+}
+
+// CanUndo returns truth if there is a command to undo.
+func (self *History) CanUndo() bool { return self.undo.Size() > 0 }
+
+// CanRedo returns truth if there is a command to redo.
+func (self *History) CanRedo() bool { return self.redo.Size() > 0 }
+
+// UndoLen returns the number of commands that can be undone.
+func (self *History) UndoLen() int { return self.undo.Size() }
+
+// RedoLen returns the number of commands that can be redone.
+func (self *History) RedoLen() int { return self.redo.Size() }
+
+// Size returns the total size of commands kept for undo as reported by
+// [Sizer].
+func (self *History) Size() int { return self.undoSize }
+
+// InTransaction returns truth if a transaction is open.
+func (self *History) InTransaction() bool { return self.open.Size() > 0 }
+
+// Do applies cmd and records it. If Do returns an error cmd is not recorded
+// and the error is returned.
+//
+// Example:
+//
+//	h := New(nil)
+//	err := h.Do(cmd)
+func (self *History) Do(cmd Command) (err error) {
+	if err = cmd.Do(); err != nil {
+		return
+	}
+	self.Add(cmd)
+	return nil
+}
+
+// Add records cmd that was already applied by the caller. If a transaction
+// is open cmd is added to it, otherwise it is pushed to the undo stack and
+// the redo stack is cleared.
+func (self *History) Add(cmd Command) {
+	if group, ok := self.open.PeekOk(); ok {
+		group.Commands = append(group.Commands, cmd)
+		return
+	}
+	self.redo.Clear()
+	self.undo.Push(cmd)
+	self.undoSize += sizeOf(cmd)
+	self.trim()
+}
+
+// Undo reverts the last recorded command and moves it to the redo stack.
+// If the command fails to undo it is left on the undo stack and the error
+// is returned.
+func (self *History) Undo() (err error) {
+	if self.InTransaction() {
+		return ErrInTransaction
+	}
+	var cmd, ok = self.undo.PeekOk()
+	if !ok {
+		return ErrNothingToUndo
+	}
+	if err = cmd.Undo(); err != nil {
+		return
+	}
+	self.undo.Pop()
+	self.undoSize -= sizeOf(cmd)
+	self.redo.Push(cmd)
+	return nil
+}
+
+// Redo applies the last undone command again and moves it to the undo
+// stack. If the command fails to apply it is left on the redo stack and the
+// error is returned.
+func (self *History) Redo() (err error) {
+	if self.InTransaction() {
+		return ErrInTransaction
+	}
+	var cmd, ok = self.redo.PeekOk()
+	if !ok {
+		return ErrNothingToRedo
+	}
+	if err = cmd.Do(); err != nil {
+		return
+	}
+	self.redo.Pop()
+	self.undo.Push(cmd)
+	self.undoSize += sizeOf(cmd)
+	self.trim()
+	return nil
+}
+
+// Begin opens a transaction named name. Commands executed until the
+// matching [History.Commit] are recorded as a single [Group]. Transactions
+// may be nested, a committed inner transaction becomes a command of the
+// outer one.
+//
+// Example:
+//
+//	h.Begin("paste")
+//	h.Do(insertText)
+//	h.Do(moveCursor)
+//	h.Commit() // both are undone with a single Undo
+func (self *History) Begin(name string) {
+	self.open.Push(&Group{Name: name})
+}
+
+// Commit closes the innermost transaction and records its commands as a
+// single [Group]. An empty transaction is discarded.
+func (self *History) Commit() (err error) {
+	var group, ok = self.open.PopOk()
+	if !ok {
+		return ErrNoTransaction
+	}
+	if len(group.Commands) > 0 {
+		self.Add(group)
+	}
+	return nil
+}
+
+// Rollback closes the innermost transaction, undoing its commands in
+// reverse order and discarding them. If a command fails to undo, commands
+// already undone are applied again, the transaction is left open and the
+// error is returned.
+func (self *History) Rollback() (err error) {
+	var group, ok = self.open.PeekOk()
+	if !ok {
+		return ErrNoTransaction
+	}
+	if err = group.Undo(); err != nil {
+		return
+	}
+	self.open.Pop()
+	return nil
+}
+
+// Clear discards all recorded commands and open transactions without
+// undoing them.
+func (self *History) Clear() {
+	self.undo.Clear()
+	self.redo.Clear()
+	self.open.Clear()
+	self.undoSize = 0
+}
+
+// trim discards the oldest commands while history limits are exceeded.
+func (self *History) trim() {
+	for {
+		var depth = self.opts.MaxDepth > 0 && self.undo.Size() > self.opts.MaxDepth
+		var size = self.opts.MaxSize > 0 && self.undoSize > self.opts.MaxSize
+		if !depth && !size {
+			return
+		}
+		var cmd, ok = self.undo.PopBottom()
+		if !ok {
+			return
+		}
+		self.undoSize -= sizeOf(cmd)
+	}
+}
+
+// Group is a [Command] made of commands applied in order and reverted in
+// reverse order. [History] records transactions as Groups.
+type Group struct {
+	// Name is the name of the group given to [History.Begin].
+	Name string
+	// Commands are the grouped commands in order of execution.
+	Commands []Command
+}
+
+// Do applies the group commands in order. If a command fails, commands
+// already applied are reverted in reverse order so that the group is
+// applied entirely or not at all, and the error is returned along with any
+// error reverting them.
+func (self *Group) Do() (err error) {
+	for i, cmd := range self.Commands {
+		if err = cmd.Do(); err == nil {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if e := self.Commands[j].Undo(); e != nil {
+				err = errors.Join(err, e)
+			}
+		}
+		return
+	}
+	return nil
+}
+
+// Undo reverts the group commands in reverse order. If a command fails,
+// commands already reverted are applied again in order so that the group
+// is reverted entirely or not at all, and the error is returned along with
+// any error applying them.
+func (self *Group) Undo() (err error) {
+	for i := len(self.Commands) - 1; i >= 0; i-- {
+		if err = self.Commands[i].Undo(); err == nil {
+			continue
+		}
+		for _, cmd := range self.Commands[i+1:] {
+			if e := cmd.Do(); e != nil {
+				err = errors.Join(err, e)
+			}
+		}
+		return
+	}
+	return nil
+}
+
+// Size returns the sum of the sizes of the group commands.
+func (self *Group) Size() (out int) {
+	for _, cmd := range self.Commands {
+		out += sizeOf(cmd)
+	}
+	return
+}
+
+// sizeOf returns the size of cmd if it implements [Sizer] or 0.
+func sizeOf(cmd Command) int {
+	if sizer, ok := cmd.(Sizer); ok {
+		return sizer.Size()
+	}
+	return 0
+}
@@ -0,0 +1,252 @@
+package undo
+
+import (
+	"errors"
+	"testing"
+)
+
+// doc is a document edited by test commands.
+type doc struct{ text string }
+
+// appendCmd appends s to a doc.
+type appendCmd struct {
+	d    *doc
+	s    string
+	fail bool
+}
+
+func (self *appendCmd) Do() error {
+	if self.fail {
+		return errors.New("fail")
+	}
+	self.d.text += self.s
+	return nil
+}
+
+func (self *appendCmd) Undo() error {
+	self.d.text = self.d.text[:len(self.d.text)-len(self.s)]
+	return nil
+}
+
+func (self *appendCmd) Size() int { return len(self.s) }
+
+func expectText(t *testing.T, d *doc, want string) {
+	t.Helper()
+	if d.text != want {
+		t.Fatalf("expected %q, got %q", want, d.text)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	var d = &doc{}
+	var h = New(nil)
+
+	if err := h.Undo(); err != ErrNothingToUndo {
+		t.Fatalf("expected ErrNothingToUndo, got %v", err)
+	}
+	if err := h.Redo(); err != ErrNothingToRedo {
+		t.Fatalf("expected ErrNothingToRedo, got %v", err)
+	}
+
+	h.Do(&appendCmd{d: d, s: "a"})
+	h.Do(&appendCmd{d: d, s: "b"})
+	h.Do(&appendCmd{d: d, s: "c"})
+	expectText(t, d, "abc")
+
+	h.Undo()
+	h.Undo()
+	expectText(t, d, "a")
+	if h.UndoLen() != 1 || h.RedoLen() != 2 {
+		t.Fatalf("expected 1/2, got %d/%d", h.UndoLen(), h.RedoLen())
+	}
+
+	h.Redo()
+	expectText(t, d, "ab")
+
+	// A new action clears redo.
+	h.Do(&appendCmd{d: d, s: "x"})
+	expectText(t, d, "abx")
+	if h.CanRedo() {
+		t.Fatal("expected redo stack to be cleared")
+	}
+
+	// A failed command is not recorded.
+	if err := h.Do(&appendCmd{d: d, s: "y", fail: true}); err == nil {
+		t.Fatal("expected error")
+	}
+	if h.UndoLen() != 3 {
+		t.Fatalf("expected 3 commands, got %d", h.UndoLen())
+	}
+
+	for h.CanUndo() {
+		h.Undo()
+	}
+	expectText(t, d, "")
+}
+
+func TestHistoryTransaction(t *testing.T) {
+	var d = &doc{}
+	var h = New(nil)
+
+	h.Begin("outer")
+	h.Do(&appendCmd{d: d, s: "a"})
+	h.Begin("inner")
+	h.Do(&appendCmd{d: d, s: "b"})
+	h.Do(&appendCmd{d: d, s: "c"})
+	if err := h.Undo(); err != ErrInTransaction {
+		t.Fatalf("expected ErrInTransaction, got %v", err)
+	}
+	h.Commit()
+	h.Do(&appendCmd{d: d, s: "d"})
+	h.Commit()
+	expectText(t, d, "abcd")
+
+	if h.UndoLen() != 1 {
+		t.Fatalf("expected 1 command, got %d", h.UndoLen())
+	}
+	h.Undo()
+	expectText(t, d, "")
+	h.Redo()
+	expectText(t, d, "abcd")
+
+	if err := h.Commit(); err != ErrNoTransaction {
+		t.Fatalf("expected ErrNoTransaction, got %v", err)
+	}
+
+	// Empty transactions are discarded.
+	h.Begin("empty")
+	h.Commit()
+	if h.UndoLen() != 1 {
+		t.Fatalf("expected 1 command, got %d", h.UndoLen())
+	}
+}
+
+func TestHistoryRollback(t *testing.T) {
+	var d = &doc{}
+	var h = New(nil)
+	h.Do(&appendCmd{d: d, s: "a"})
+	h.Begin("tx")
+	h.Do(&appendCmd{d: d, s: "b"})
+	h.Do(&appendCmd{d: d, s: "c"})
+	if err := h.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	expectText(t, d, "a")
+	if h.InTransaction() || h.UndoLen() != 1 {
+		t.Fatal("expected rolled back transaction to be discarded")
+	}
+	if err := h.Rollback(); err != ErrNoTransaction {
+		t.Fatalf("expected ErrNoTransaction, got %v", err)
+	}
+}
+
+// addCmd adds n to v. Its Do and Undo fail as many times as set.
+type addCmd struct {
+	v                *int
+	n                int
+	failDo, failUndo int
+}
+
+func (self *addCmd) Do() error {
+	if self.failDo > 0 {
+		self.failDo--
+		return errors.New("fail do")
+	}
+	*self.v += self.n
+	return nil
+}
+
+func (self *addCmd) Undo() error {
+	if self.failUndo > 0 {
+		self.failUndo--
+		return errors.New("fail undo")
+	}
+	*self.v -= self.n
+	return nil
+}
+
+func TestHistoryGroupFailure(t *testing.T) {
+	var (
+		v   int
+		h   = New(nil)
+		mid = &addCmd{v: &v, n: 10}
+	)
+	var expect = func(want int) {
+		t.Helper()
+		if v != want {
+			t.Fatalf("expected %d, got %d", want, v)
+		}
+	}
+	h.Begin("tx")
+	h.Do(&addCmd{v: &v, n: 1})
+	h.Do(mid)
+	h.Do(&addCmd{v: &v, n: 100})
+	h.Commit()
+
+	mid.failUndo = 1
+	if err := h.Undo(); err == nil {
+		t.Fatal("expected undo to fail")
+	}
+	expect(111)
+	if err := h.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	expect(0)
+
+	mid.failDo = 1
+	if err := h.Redo(); err == nil {
+		t.Fatal("expected redo to fail")
+	}
+	expect(0)
+	if err := h.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	expect(111)
+
+	h.Begin("tx")
+	h.Do(&addCmd{v: &v, n: 1000, failUndo: 1})
+	h.Do(&addCmd{v: &v, n: 10000})
+	if err := h.Rollback(); err == nil {
+		t.Fatal("expected rollback to fail")
+	}
+	expect(11111)
+	if !h.InTransaction() {
+		t.Fatal("expected failed rollback to keep the transaction open")
+	}
+	if err := h.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	expect(111)
+}
+
+func TestHistoryLimits(t *testing.T) {
+	var d = &doc{}
+	var h = New(&Options{MaxDepth: 2})
+	for _, s := range []string{"a", "b", "c"} {
+		h.Do(&appendCmd{d: d, s: s})
+	}
+	if h.UndoLen() != 2 {
+		t.Fatalf("expected 2 commands, got %d", h.UndoLen())
+	}
+	h.Undo()
+	h.Undo()
+	expectText(t, d, "a")
+
+	d = &doc{}
+	h = New(&Options{MaxSize: 5})
+	for _, s := range []string{"aa", "bb", "cc"} {
+		h.Do(&appendCmd{d: d, s: s})
+	}
+	if h.UndoLen() != 2 || h.Size() != 4 {
+		t.Fatalf("expected 2 commands of size 4, got %d of size %d", h.UndoLen(), h.Size())
+	}
+	h.Do(&appendCmd{d: d, s: "toolarge"})
+	if h.UndoLen() != 0 || h.Size() != 0 {
+		t.Fatalf("expected empty history, got %d of size %d", h.UndoLen(), h.Size())
+	}
+
+	h.Clear()
+	if h.CanUndo() || h.CanRedo() {
+		t.Fatal("expected empty history")
+	}
+}